@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	readline "github.com/Jestzer/readlineJestzer"
+	"github.com/fatih/color"
+)
+
+// Activation keys can come from here or from a file given with --activation-key-file.
+// They're never asked for at a prompt, since they'd end up on screen and in your terminal's scrollback.
+const activationKeyEnvironmentVariable = "MPM_ACTIVATION_KEY"
+
+// The script MATLAB ships with for activating itself.
+func activationScript(installPath string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(installPath, "bin", "win64", "activate_matlab.exe")
+	}
+	return filepath.Join(installPath, "bin", "activate_matlab.sh")
+}
+
+// Find the activation key from the command line's key file or the environment. The second value says where it came from.
+func activationKeyFromArgs(args []string) (string, string, error) {
+	if keyFiles := argumentValues(args, "--activation-key-file"); len(keyFiles) > 0 {
+		keyFile := keyFiles[len(keyFiles)-1]
+		key, err := readActivationKeyFile(keyFile)
+		return key, keyFile, err
+	}
+	if key := strings.TrimSpace(os.Getenv(activationKeyEnvironmentVariable)); key != "" {
+		return key, activationKeyEnvironmentVariable, nil
+	}
+	return "", "", nil
+}
+
+func readActivationKeyFile(keyFile string) (string, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" || strings.ContainsAny(key, "\r\n") {
+		return "", fmt.Errorf("\"%s\" should contain only your activation key", keyFile)
+	}
+	return key, nil
+}
+
+// Activate a fresh installation without any of MATLAB's activation windows, so it doesn't ask to be activated on first launch.
+func activateInstallation(rl *readline.Instance, args []string, installPath string) error {
+	redText := color.New(color.FgRed).SprintFunc()
+
+	script := activationScript(installPath)
+	if _, err := os.Stat(script); err != nil {
+		return fmt.Errorf("could not find MATLAB's activation script at \"%s\"", script)
+	}
+
+	key, keySource, err := activationKeyFromArgs(args)
+	if err != nil {
+		return err
+	}
+	for key == "" {
+		keyFile := promptUser(rl, "Enter the path to a file containing your activation key. You can also set "+activationKeyEnvironmentVariable+" before running this program.")
+		if keyFile == "" {
+			continue
+		}
+		key, err = readActivationKeyFile(keyFile)
+		if err != nil {
+			fmt.Println(redText("Error reading activation key: ", err))
+			continue
+		}
+		keySource = keyFile
+	}
+	fmt.Println("Using the activation key from " + keySource + ".")
+
+	username := localUsername()
+	if answer := promptUser(rl, "Enter the username MATLAB should be activated for. Press Enter to use \""+username+"\""); answer != "" {
+		username = answer
+	}
+
+	// The properties file holds the key, so only you get to read it, and it's deleted as soon as we're done.
+	tempDir, err := os.MkdirTemp("", "mpm-activation")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	propertiesPath := filepath.Join(tempDir, "activate.ini")
+	properties := strings.Join([]string{
+		"isSilent=true",
+		"activateCommand=activateOnline",
+		"activationKey=" + key,
+		"userName=" + username,
+		"installLicenseFileDir=" + filepath.Join(installPath, "licenses"),
+		"installLicenseFileName=license.lic",
+	}, "\n") + "\n"
+	err = os.WriteFile(propertiesPath, []byte(properties), 0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Activating, please wait.")
+	cmd := exec.Command(script, "-propertiesFile", propertiesPath)
+	cmd.Stdout = &redactingWriter{writer: os.Stdout, secret: key}
+	cmd.Stderr = &redactingWriter{writer: os.Stderr, secret: key}
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("MATLAB's activation script failed. See the output above for more information: %w", err)
+	}
+
+	licensePath := filepath.Join(installPath, "licenses", "license.lic")
+	if _, err := os.Stat(licensePath); err != nil {
+		return fmt.Errorf("the activation script finished, but no license was created at \"%s\"", licensePath)
+	}
+	return nil
+}
+
+// Hides a secret from anything passed through it, just in case a program decides to print it.
+type redactingWriter struct {
+	writer io.Writer
+	secret string
+}
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	if rw.secret == "" || !bytes.Contains(p, []byte(rw.secret)) {
+		return rw.writer.Write(p)
+	}
+	_, err := rw.writer.Write(bytes.ReplaceAll(p, []byte(rw.secret), []byte("********")))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
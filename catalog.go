@@ -0,0 +1,297 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// The releases MPM can install for each platform.
+func validReleasesFor(platform string) []string {
+	if platform == "macOSARM" {
+		return []string{
+			"R2023b", "R2024a", "R2024b", "R2025a",
+		}
+	}
+	return []string{
+		"R2017b", "R2018a", "R2018b", "R2019a", "R2019b", "R2020a", "R2020b",
+		"R2021a", "R2021b", "R2022a", "R2022b", "R2023a", "R2023b", "R2024a", "R2024b", "R2025a",
+	}
+}
+
+// Products that were added in each release, per platform. Every release at or after the key includes them.
+func newProductsFor(platform string) map[string]string {
+	switch platform {
+	case "windows":
+		return map[string]string{
+			"R2023b": "Simulink_Fault_Analyzer Polyspace_Test",
+			"R2023a": "MATLAB_Test C2000_Microcontroller_Blockset",
+			"R2022b": "Medical_Imaging_Toolbox Simscape_Battery",
+			"R2022a": "Wireless_Testbench Bluetooth_Toolbox DSP_HDL_Toolbox Requirements_Toolbox Industrial_Communication_Toolbox",
+			"R2021b": "Signal_Integrity_Toolbox RF_PCB_Toolbox",
+			"R2021a": "Satellite_Communications_Toolbox DDS_Blockset",
+			"R2020b": "UAV_Toolbox Radar_Toolbox Lidar_Toolbox Deep_Learning_HDL_Toolbox",
+			"R2020a": "Simulink_Compiler Motor_Control_Blockset MATLAB_Web_App_Server Wireless_HDL_Toolbox",
+			"R2019b": "ROS_Toolbox Navigation_Toolbox",
+			"R2019a": "System_Composer SoC_Blockset SerDes_Toolbox Reinforcement_Learning_Toolbox Audio_Toolbox Mixed-Signal_Blockset AUTOSAR_Blockset MATLAB_Parallel_Server Polyspace_Bug_Finder_Server Polyspace_Code_Prover_Server Automated_Driving_Toolbox Computer_Vision_Toolbox",
+			"R2018b": "Communications_Toolbox Simscape_Electrical Sensor_Fusion_and_Tracking_Toolbox Deep_Learning_Toolbox 5G_Toolbox WLAN_Toolbox LTE_Toolbox",
+			"R2018a": "Predictive_Maintenance_Toolbox Vehicle_Dynamics_Blockset",
+			"R2017b": "Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Bioinformatics_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DSP_System_Toolbox Data_Acquisition_Toolbox Database_Toolbox Datafeed_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox GPU_Coder Global_Optimization_Toolbox HDL_Coder HDL_Verifier Image_Acquisition_Toolbox Image_Processing_Toolbox Instrument_Control_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Production_Server MATLAB_Report_Generator Mapping_Toolbox Model_Predictive_Control_Toolbox Model-Based_Calibration_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Polyspace_Bug_Finder Polyspace_Code_Prover Powertrain_Blockset RF_Blockset RF_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Driveline Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Desktop_Real-Time Simulink_PLC_Coder Simulink_Real-Time Simulink_Report_Generator Simulink_Test Spreadsheet_Link Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Identification_Toolbox Text_Analytics_Toolbox Vehicle_Network_Toolbox Vision_HDL_Toolbox Wavelet_Toolbox",
+		}
+
+	case "linux":
+		return map[string]string{
+			"R2023b": "Simulink_Fault_Analyzer Polyspace_Test Simulink_Desktop_Real-Time",
+			"R2023a": "MATLAB_Test C2000_Microcontroller_Blockset",
+			"R2022b": "Medical_Imaging_Toolbox Simscape_Battery",
+			"R2022a": "Wireless_Testbench Simulink_Real-Time Bluetooth_Toolbox DSP_HDL_Toolbox Requirements_Toolbox Industrial_Communication_Toolbox",
+			"R2021b": "Signal_Integrity_Toolbox RF_PCB_Toolbox",
+			"R2021a": "Satellite_Communications_Toolbox DDS_Blockset",
+			"R2020b": "UAV_Toolbox Radar_Toolbox Lidar_Toolbox Deep_Learning_HDL_Toolbox",
+			"R2020a": "Simulink_Compiler Motor_Control_Blockset MATLAB_Web_App_Server Wireless_HDL_Toolbox",
+			"R2019b": "ROS_Toolbox Simulink_PLC_Coder Navigation_Toolbox",
+			"R2019a": "System_Composer SoC_Blockset SerDes_Toolbox Reinforcement_Learning_Toolbox Audio_Toolbox Mixed-Signal_Blockset AUTOSAR_Blockset MATLAB_Parallel_Server Polyspace_Bug_Finder_Server Polyspace_Code_Prover_Server Automated_Driving_Toolbox Computer_Vision_Toolbox",
+			"R2018b": "Communications_Toolbox Simscape_Electrical Sensor_Fusion_and_Tracking_Toolbox Deep_Learning_Toolbox 5G_Toolbox WLAN_Toolbox LTE_Toolbox",
+			"R2018a": "Predictive_Maintenance_Toolbox Vehicle_Network_Toolbox Vehicle_Dynamics_Blockset",
+			"R2017b": "Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Bioinformatics_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DSP_System_Toolbox Database_Toolbox Datafeed_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox GPU_Coder Global_Optimization_Toolbox HDL_Coder HDL_Verifier Image_Acquisition_Toolbox Image_Processing_Toolbox Instrument_Control_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Production_Server MATLAB_Report_Generator Mapping_Toolbox Model_Predictive_Control_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Polyspace_Bug_Finder Polyspace_Code_Prover Powertrain_Blockset RF_Blockset RF_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Driveline Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Report_Generator Simulink_Test Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Identification_Toolbox Text_Analytics_Toolbox Vision_HDL_Toolbox Wavelet_Toolbox",
+		}
+
+	case "macOSx64":
+		return map[string]string{
+			"R2023b": "Simulink_Fault_Analyzer Polyspace_Test",
+			"R2023a": "MATLAB_Test",
+			"R2022b": "Medical_Imaging_Toolbox Simscape_Battery",
+			"R2022a": "Bluetooth_Toolbox DSP_HDL_Toolbox Requirements_Toolbox Industrial_Communication_Toolbox",
+			"R2021b": "RF_PCB_Toolbox",
+			"R2021a": "Satellite_Communications_Toolbox DDS_Blockset",
+			"R2020b": "UAV_Toolbox Radar_Toolbox Lidar_Toolbox",
+			"R2020a": "Simulink_Compiler Motor_Control_Blockset MATLAB_Web_App_Server Wireless_HDL_Toolbox",
+			"R2019b": "ROS_Toolbox Simulink_PLC_Coder Navigation_Toolbox",
+			"R2019a": "System_Composer SerDes_Toolbox Reinforcement_Learning_Toolbox Audio_Toolbox Mixed-Signal_Blockset AUTOSAR_Blockset Polyspace_Bug_Finder_Server Polyspace_Code_Prover_Server Automated_Driving_Toolbox Computer_Vision_Toolbox",
+			"R2018b": "Communications_Toolbox Simscape_Electrical Sensor_Fusion_and_Tracking_Toolbox Deep_Learning_Toolbox 5G_Toolbox WLAN_Toolbox LTE_Toolbox",
+			"R2018a": "Predictive_Maintenance_Toolbox Vehicle_Dynamics_Blockset",
+			"R2017b": "Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Bioinformatics_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DSP_System_Toolbox Database_Toolbox Datafeed_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox Global_Optimization_Toolbox HDL_Coder Image_Acquisition_Toolbox Image_Processing_Toolbox Instrument_Control_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Production_Server MATLAB_Report_Generator Mapping_Toolbox Model_Predictive_Control_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Polyspace_Bug_Finder Polyspace_Code_Prover Powertrain_Blockset RF_Blockset RF_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Driveline Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Desktop_Real-Time Simulink_Report_Generator Simulink_Test Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Identification_Toolbox Text_Analytics_Toolbox Wavelet_Toolbox",
+		}
+
+	case "macOSARM":
+		return map[string]string{
+			"R2023b": "5G_Toolbox AUTOSAR_Blockset Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Audio_Toolbox Automated_Driving_Toolbox Bioinformatics_Toolbox Bluetooth_Toolbox Communications_Toolbox Computer_Vision_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DDS_Blockset DSP_HDL_Toolbox DSP_System_Toolbox Database_Toolbox Datafeed_Toolbox Deep_Learning_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox Global_Optimization_Toolbox HDL_Coder Image_Acquisition_Toolbox Image_Processing_Toolbox Industrial_Communication_Toolbox Instrument_Control_Toolbox LTE_Toolbox Lidar_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Report_Generator MATLAB_Test Mapping_Toolbox Medical_Imaging_Toolbox Mixed-Signal_Blockset Model_Predictive_Control_Toolbox Motor_Control_Blockset Navigation_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Powertrain_Blockset Predictive_Maintenance_Toolbox RF_Blockset RF_PCB_Toolbox RF_Toolbox ROS_Toolbox Radar_Toolbox Reinforcement_Learning_Toolbox Requirements_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Satellite_Communications_Toolbox Sensor_Fusion_and_Tracking_Toolbox SerDes_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Battery Simscape_Driveline Simscape_Electrical Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Compiler Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Fault_Analyzer Simulink_PLC_Coder Simulink_Report_Generator Simulink_Test Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Composer System_Identification_Toolbox Text_Analytics_Toolbox UAV_Toolbox Vehicle_Dynamics_Blockset WLAN_Toolbox Wavelet_Toolbox Wireless_HDL_Toolbox",
+		}
+	}
+	return nil
+}
+
+// Products that were removed after each release, per platform. Every release at or before the key includes them.
+func oldProductsFor(platform string) map[string]string {
+	switch platform {
+	case "windows":
+		return map[string]string{
+			"R2024b": "Filter_Design_HDL_Coder",
+			"R2021b": "Simulink_Requirements OPC_Toolbox",
+			"R2020b": "Trading_Toolbox",
+			"R2019b": "LTE_HDL_Toolbox",
+			"R2018b": "Audio_System_Toolbox Automated_Driving_System_Toolbox Computer_Vision_System_Toolbox MATLAB_Distributed_Computing_Server",
+			"R2018a": "Communications_System_Toolbox LTE_System_Toolbox Neural_Network_Toolbox Simscape_Electronics Simscape_Power_Systems WLAN_System_Toolbox",
+		}
+
+	case "linux":
+		return map[string]string{
+			"R2024b": "Filter_Design_HDL_Coder",
+			"R2021b": "Simulink_Requirements",
+			"R2020b": "Trading_Toolbox",
+			"R2019b": "LTE_HDL_Toolbox",
+			"R2018b": "Audio_System_Toolbox Automated_Driving_System_Toolbox Computer_Vision_System_Toolbox MATLAB_Distributed_Computing_Server",
+			"R2018a": "Communications_System_Toolbox LTE_System_Toolbox Neural_Network_Toolbox Simscape_Electronics Simscape_Power_Systems WLAN_System_Toolbox",
+		}
+
+	case "macOSx64":
+		return map[string]string{
+			"R2024b": "Filter_Design_HDL_Coder",
+			"R2021b": "Simulink_Requirements MATLAB_Parallel_Server",
+			"R2020b": "Trading_Toolbox",
+			"R2019b": "LTE_HDL_Toolbox",
+			"R2018b": "Audio_System_Toolbox Automated_Driving_System_Toolbox Computer_Vision_System_Toolbox MATLAB_Distributed_Computing_Server",
+			"R2018a": "Communications_System_Toolbox LTE_System_Toolbox Neural_Network_Toolbox Simscape_Electronics Simscape_Power_Systems WLAN_System_Toolbox",
+		}
+	case "macOSARM":
+		return map[string]string{
+			"R2024b": "Filter_Design_HDL_Coder",
+		}
+	}
+	return nil
+}
+
+// Assemble the full product list based on your release and platform.
+// Notes:
+// - No old products are needed for macOSARM at the moment.
+// - No new products were added in R2024a, R2024b, nor R2025a for any platform, so they are ommitted entries.
+func productsForRelease(platform string, release string) []string {
+	var allProducts []string
+
+	// Use a loop to go through the "new" products to add the appropriate products.
+	for releaseLoop, product := range newProductsFor(platform) {
+		if release >= releaseLoop {
+			allProducts = append(allProducts, strings.Fields(product)...)
+		}
+	}
+
+	// The same logic goes for old products, it just uses <= instead of >=.
+	for releaseLoop, product := range oldProductsFor(platform) {
+		if release <= releaseLoop {
+			allProducts = append(allProducts, strings.Fields(product)...)
+		}
+	}
+	return allProducts
+}
+
+// The FlexNet feature each product is checked out as, where it isn't the same as the product's name.
+// Many of these still use the product's original name from decades ago.
+var licenseFeatureNames = map[string]string{
+	"Audio_Toolbox":                           "Audio_System_Toolbox",
+	"Communications_System_Toolbox":           "Communication_Toolbox",
+	"Communications_Toolbox":                  "Communication_Toolbox",
+	"Computer_Vision_System_Toolbox":          "Video_and_Image_Blockset",
+	"Computer_Vision_Toolbox":                 "Video_and_Image_Blockset",
+	"Control_System_Toolbox":                  "Control_Toolbox",
+	"Data_Acquisition_Toolbox":                "Data_Acq_Toolbox",
+	"Deep_Learning_Toolbox":                   "Neural_Network_Toolbox",
+	"DSP_System_Toolbox":                      "Signal_Blocks",
+	"Embedded_Coder":                          "RTW_Embedded_Coder",
+	"Financial_Instruments_Toolbox":           "Fin_Instruments_Toolbox",
+	"Fixed-Point_Designer":                    "Fixed_Point_Toolbox",
+	"Fuzzy_Logic_Toolbox":                     "Fuzzy_Toolbox",
+	"Global_Optimization_Toolbox":             "GADS_Toolbox",
+	"HDL_Coder":                               "Simulink_HDL_Coder",
+	"HDL_Verifier":                            "EDA_Simulator_Link",
+	"Image_Processing_Toolbox":                "Image_Toolbox",
+	"Instrument_Control_Toolbox":              "Instr_Control_Toolbox",
+	"LTE_System_Toolbox":                      "LTE_Toolbox",
+	"Mapping_Toolbox":                         "MAP_Toolbox",
+	"MATLAB_Compiler":                         "Compiler",
+	"MATLAB_Distributed_Computing_Server":     "MATLAB_Distrib_Comp_Engine",
+	"MATLAB_Parallel_Server":                  "MATLAB_Distrib_Comp_Engine",
+	"MATLAB_Report_Generator":                 "MATLAB_Report_Gen",
+	"Mixed-Signal_Blockset":                   "Mixed_Signal_Blockset",
+	"Model_Predictive_Control_Toolbox":        "MPC_Toolbox",
+	"Model-Based_Calibration_Toolbox":         "MBC_Toolbox",
+	"Parallel_Computing_Toolbox":              "Distrib_Computing_Toolbox",
+	"Partial_Differential_Equation_Toolbox":   "PDE_Toolbox",
+	"Polyspace_Bug_Finder":                    "PolySpace_Bug_Finder",
+	"Reinforcement_Learning_Toolbox":          "Reinforcement_Learn_Toolbox",
+	"RF_Blockset":                             "SimRF",
+	"Robust_Control_Toolbox":                  "Robust_Toolbox",
+	"Sensor_Fusion_and_Tracking_Toolbox":      "Sensor_Fusion_and_Tracking",
+	"Signal_Processing_Toolbox":               "Signal_Toolbox",
+	"Simscape_Driveline":                      "SimDriveline",
+	"Simscape_Electrical":                     "Power_System_Blocks",
+	"Simscape_Fluids":                         "SimHydraulics",
+	"Simscape_Multibody":                      "SimMechanics",
+	"Simscape_Power_Systems":                  "Power_System_Blocks",
+	"Simulink":                                "SIMULINK",
+	"Simulink_3D_Animation":                   "Virtual_Reality_Toolbox",
+	"Simulink_Check":                          "SL_Verification_Validation",
+	"Simulink_Coder":                          "Real-Time_Workshop",
+	"Simulink_Design_Optimization":            "Simulink_Design_Optim",
+	"Simulink_Desktop_Real-Time":              "Real-Time_Win_Target",
+	"Simulink_Real-Time":                      "XPC_Target",
+	"Simulink_Report_Generator":               "SIMULINK_Report_Gen",
+	"Requirements_Toolbox":                    "Simulink_Requirements",
+	"Statistics_and_Machine_Learning_Toolbox": "Statistics_Toolbox",
+	"Symbolic_Math_Toolbox":                   "Symbolic_Toolbox",
+	"System_Identification_Toolbox":           "Identification_Toolbox",
+	"WLAN_Toolbox":                            "WLAN_System_Toolbox",
+}
+
+// Products that don't need a license to be used.
+var unlicensedProducts = map[string]struct{}{
+	"Network_License_Manager": {},
+}
+
+// The FlexNet feature a product is checked out as. Most products use their own name.
+func licenseFeatureFor(product string) string {
+	if feature, exists := licenseFeatureNames[product]; exists {
+		return feature
+	}
+	return product
+}
+
+// The products that took over from products MathWorks has stopped making. Most of these are renames, and the rest were folded into
+// another product.
+var productReplacements = map[string]string{
+	"Audio_System_Toolbox":                "Audio_Toolbox",
+	"Automated_Driving_System_Toolbox":    "Automated_Driving_Toolbox",
+	"Communications_System_Toolbox":       "Communications_Toolbox",
+	"Computer_Vision_System_Toolbox":      "Computer_Vision_Toolbox",
+	"Filter_Design_HDL_Coder":             "DSP_HDL_Toolbox",
+	"LTE_HDL_Toolbox":                     "Wireless_HDL_Toolbox",
+	"LTE_System_Toolbox":                  "LTE_Toolbox",
+	"MATLAB_Distributed_Computing_Server": "MATLAB_Parallel_Server",
+	"Neural_Network_Toolbox":              "Deep_Learning_Toolbox",
+	"OPC_Toolbox":                         "Industrial_Communication_Toolbox",
+	"Simscape_Electronics":                "Simscape_Electrical",
+	"Simscape_Power_Systems":              "Simscape_Electrical",
+	"Simulink_Requirements":               "Requirements_Toolbox",
+	"WLAN_System_Toolbox":                 "WLAN_Toolbox",
+}
+
+// Which of the products you've picked won't be in any release after this one.
+func productsInFinalRelease(platform string, release string, products []string) []string {
+	var finalProducts []string
+	for _, product := range strings.Fields(oldProductsFor(platform)[release]) {
+		for _, selectedProduct := range products {
+			if selectedProduct == product {
+				finalProducts = append(finalProducts, product)
+				break
+			}
+		}
+	}
+	return finalProducts
+}
+
+// Products that were folded into their replacement rather than renamed, so one name can't stand in for the other.
+var foldedProducts = map[string]bool{
+	"Filter_Design_HDL_Coder": true,
+}
+
+// Swap product names that belong to other releases for what they're called in this one, such as Neural_Network_Toolbox for
+// Deep_Learning_Toolbox in R2018b and newer, or the other way around in R2018a and older. A product that was made by merging
+// others maps back to all of them. Returns the products to use and a notice for each name that was swapped.
+func resolveProductAliases(products []string, availableProducts []string, release string) ([]string, []string) {
+	available := make(map[string]bool, len(availableProducts))
+	for _, product := range availableProducts {
+		available[product] = true
+	}
+
+	var resolved []string
+	var notices []string
+	for _, product := range products {
+		if available[product] {
+			resolved = appendUnique(resolved, product)
+			continue
+		}
+		if replacement, found := productReplacements[product]; found && !foldedProducts[product] && available[replacement] {
+			resolved = appendUnique(resolved, replacement)
+			notices = append(notices, product+" is called "+replacement+" in "+release+", so "+replacement+" will be used instead.")
+			continue
+		}
+		var formerNames []string
+		for oldProduct, replacement := range productReplacements {
+			if replacement == product && !foldedProducts[oldProduct] && available[oldProduct] {
+				formerNames = append(formerNames, oldProduct)
+			}
+		}
+		if len(formerNames) == 0 {
+			resolved = appendUnique(resolved, product) // Left for checkProductsExist to report.
+			continue
+		}
+		sort.Strings(formerNames)
+		for _, formerName := range formerNames {
+			resolved = appendUnique(resolved, formerName)
+		}
+		if len(formerNames) == 1 {
+			notices = append(notices, product+" is called "+formerNames[0]+" in "+release+", so "+formerNames[0]+" will be used instead.")
+		} else {
+			notices = append(notices, product+" was made from "+strings.Join(formerNames, " and ")+", which are what "+release+" has, so those will be used instead.")
+		}
+	}
+	return resolved, notices
+}
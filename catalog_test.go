@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveProductAliases(t *testing.T) {
+	tests := []struct {
+		release  string
+		products string
+		want     string
+		notices  int
+	}{
+		{"R2025a", "MATLAB Communications_System_Toolbox Computer_Vision_System_Toolbox", "MATLAB Communications_Toolbox Computer_Vision_Toolbox", 2},
+		{"R2025a", "MATLAB_Distributed_Computing_Server Neural_Network_Toolbox", "MATLAB_Parallel_Server Deep_Learning_Toolbox", 2},
+		{"R2017b", "MATLAB Parallel_Computing_Toolbox MATLAB_Parallel_Server", "MATLAB Parallel_Computing_Toolbox MATLAB_Distributed_Computing_Server", 1},
+		{"R2018a", "Simscape_Electrical", "Simscape_Electronics Simscape_Power_Systems", 1},
+		{"R2025a", "Simscape_Electronics Simscape_Power_Systems", "Simscape_Electrical", 2},
+		{"R2023a", "Filter_Design_HDL_Coder Requirements_Toolbox", "Filter_Design_HDL_Coder Requirements_Toolbox", 0},
+
+		// Folded products aren't renames, so they're left for checkProductsExist to report.
+		{"R2025a", "Filter_Design_HDL_Coder", "Filter_Design_HDL_Coder", 0},
+		{"R2025a", "Not_A_Product", "Not_A_Product", 0},
+	}
+	for _, test := range tests {
+		got, notices := resolveProductAliases(strings.Fields(test.products), productsForRelease("linux", test.release), test.release)
+		if strings.Join(got, " ") != test.want || len(notices) != test.notices {
+			t.Errorf("%s %q = %q with %d notices, want %q with %d", test.release, test.products, strings.Join(got, " "), len(notices), test.want, test.notices)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// The Linux distributions and versions MathWorks lists as supported for a release, along with the oldest glibc it runs on.
+// Red Hat versions also cover its rebuilds (CentOS, Rocky Linux, AlmaLinux, and Oracle Linux.)
+type linuxSupport struct {
+	MinimumGlibc string
+	Ubuntu       []string
+	Debian       []string
+	RedHat       []string
+	SUSE         []string
+}
+
+var linuxSupportTable = map[string]linuxSupport{
+	"R2017b": {MinimumGlibc: "2.12", Ubuntu: []string{"14.04", "16.04", "17.04"}, Debian: []string{"8", "9"}, RedHat: []string{"6", "7"}, SUSE: []string{"12"}},
+	"R2018a": {MinimumGlibc: "2.12", Ubuntu: []string{"14.04", "16.04", "17.10"}, Debian: []string{"8", "9"}, RedHat: []string{"6", "7"}, SUSE: []string{"12"}},
+	"R2018b": {MinimumGlibc: "2.12", Ubuntu: []string{"16.04", "18.04"}, Debian: []string{"8", "9"}, RedHat: []string{"6", "7"}, SUSE: []string{"12"}},
+	"R2019a": {MinimumGlibc: "2.12", Ubuntu: []string{"16.04", "18.04"}, Debian: []string{"9"}, RedHat: []string{"6", "7"}, SUSE: []string{"12", "15"}},
+	"R2019b": {MinimumGlibc: "2.17", Ubuntu: []string{"16.04", "18.04"}, Debian: []string{"9", "10"}, RedHat: []string{"7", "8"}, SUSE: []string{"12", "15"}},
+	"R2020a": {MinimumGlibc: "2.17", Ubuntu: []string{"16.04", "18.04"}, Debian: []string{"9", "10"}, RedHat: []string{"7", "8"}, SUSE: []string{"12", "15"}},
+	"R2020b": {MinimumGlibc: "2.17", Ubuntu: []string{"18.04", "20.04"}, Debian: []string{"9", "10"}, RedHat: []string{"7", "8"}, SUSE: []string{"12", "15"}},
+	"R2021a": {MinimumGlibc: "2.17", Ubuntu: []string{"18.04", "20.04"}, Debian: []string{"10"}, RedHat: []string{"7", "8"}, SUSE: []string{"12", "15"}},
+	"R2021b": {MinimumGlibc: "2.17", Ubuntu: []string{"18.04", "20.04"}, Debian: []string{"10"}, RedHat: []string{"7", "8"}, SUSE: []string{"12", "15"}},
+	"R2022a": {MinimumGlibc: "2.17", Ubuntu: []string{"18.04", "20.04"}, Debian: []string{"10", "11"}, RedHat: []string{"7", "8"}, SUSE: []string{"12", "15"}},
+	"R2022b": {MinimumGlibc: "2.17", Ubuntu: []string{"20.04", "22.04"}, Debian: []string{"10", "11"}, RedHat: []string{"7", "8", "9"}, SUSE: []string{"12", "15"}},
+	"R2023a": {MinimumGlibc: "2.17", Ubuntu: []string{"20.04", "22.04"}, Debian: []string{"11"}, RedHat: []string{"7", "8", "9"}, SUSE: []string{"15"}},
+	"R2023b": {MinimumGlibc: "2.28", Ubuntu: []string{"20.04", "22.04"}, Debian: []string{"11", "12"}, RedHat: []string{"8", "9"}, SUSE: []string{"15"}},
+	"R2024a": {MinimumGlibc: "2.28", Ubuntu: []string{"20.04", "22.04"}, Debian: []string{"11", "12"}, RedHat: []string{"8", "9"}, SUSE: []string{"15"}},
+	"R2024b": {MinimumGlibc: "2.28", Ubuntu: []string{"22.04", "24.04"}, Debian: []string{"11", "12"}, RedHat: []string{"8", "9"}, SUSE: []string{"15"}},
+	"R2025a": {MinimumGlibc: "2.28", Ubuntu: []string{"22.04", "24.04"}, Debian: []string{"12"}, RedHat: []string{"8", "9"}, SUSE: []string{"15"}},
+}
+
+// How well a release fits the system it's being installed on. Status is "supported", "untested", or "unsupported".
+type releaseCompatibility struct {
+	Status string
+	Reason string
+}
+
+// Which column of the support table a distribution belongs in, and its version as the table writes it. Ubuntu keeps its full
+// version (22.04) and everyone else just their major version. Derivatives such as Linux Mint number themselves differently,
+// so they aren't counted as the distribution they're based on.
+func linuxDistro(osRelease map[string]string) (string, string) {
+	version := osRelease["VERSION_ID"]
+	switch strings.ToLower(osRelease["ID"]) {
+	case "ubuntu":
+		return "ubuntu", version
+	case "debian":
+		major, _, _ := strings.Cut(version, ".")
+		return "debian", major
+	case "rhel", "centos", "rocky", "almalinux", "ol":
+		major, _, _ := strings.Cut(version, ".")
+		return "redhat", major
+	case "sles", "sled", "opensuse-leap":
+		major, _, _ := strings.Cut(version, ".")
+		return "suse", major
+	}
+	return "", version
+}
+
+func (support linuxSupport) versionsFor(distro string) []string {
+	switch distro {
+	case "ubuntu":
+		return support.Ubuntu
+	case "debian":
+		return support.Debian
+	case "redhat":
+		return support.RedHat
+	case "suse":
+		return support.SUSE
+	}
+	return nil
+}
+
+// Compare a release's support table with this system's distribution and glibc. An empty glibc version skips that part of the check.
+func checkReleaseCompatibility(release string, osRelease map[string]string, glibc string) releaseCompatibility {
+	support, found := linuxSupportTable[release]
+	if !found {
+		return releaseCompatibility{"untested", "no support information"}
+	}
+	if glibc != "" && !versionAtLeast(glibc, support.MinimumGlibc) {
+		return releaseCompatibility{"unsupported", "needs glibc " + support.MinimumGlibc + " or newer"}
+	}
+
+	distro, version := linuxDistro(osRelease)
+	versions := support.versionsFor(distro)
+	if len(versions) == 0 || version == "" {
+		return releaseCompatibility{"untested", "not tested on this distribution"}
+	}
+	for _, supportedVersion := range versions {
+		if version == supportedVersion {
+			return releaseCompatibility{"supported", ""}
+		}
+	}
+	if !versionAtLeast(version, versions[0]) {
+		return releaseCompatibility{"unsupported", "this version of your distribution is too old"}
+	}
+
+	// A version or so past the newest one listed usually still works. Much further than that and the libraries MATLAB was built
+	// against are long gone.
+	newest := versions[len(versions)-1]
+	if versionMajor(version) > versionMajor(newest)+newerVersionsTolerated(distro) {
+		return releaseCompatibility{"unsupported", "this version of your distribution is too new"}
+	}
+	return releaseCompatibility{"untested", "not tested on this version of your distribution"}
+}
+
+// How many major versions past the newest supported one are still worth trying. Ubuntu's major version is the year, and its
+// LTS releases are two years apart.
+func newerVersionsTolerated(distro string) int {
+	if distro == "ubuntu" {
+		return 2
+	}
+	return 1
+}
+
+// The first part of a version such as 22.04 or 9.4.
+func versionMajor(version string) int {
+	major, _, _ := strings.Cut(version, ".")
+	number, _ := strconv.Atoi(major)
+	return number
+}
+
+// Print how each release fits this system. Returns each release's compatibility and the newest supported release, which is empty
+// if none of them are.
+func printReleaseCompatibility(releases []string) (map[string]releaseCompatibility, string) {
+	greenText := color.New(color.FgHiGreen).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+	redText := color.New(color.FgRed).SprintFunc()
+
+	osRelease, _ := readOSRelease("/etc/os-release")
+	glibc := glibcVersion()
+	systemName := osRelease["PRETTY_NAME"]
+	if systemName == "" {
+		systemName = "this system"
+	}
+	if glibc != "" {
+		systemName += " with glibc " + glibc
+	}
+	fmt.Println("How each release fits " + systemName + ":")
+
+	compatibility := make(map[string]releaseCompatibility)
+	newestSupported := ""
+	for _, release := range releases {
+		result := checkReleaseCompatibility(release, osRelease, glibc)
+		compatibility[release] = result
+		line := release + ": " + result.Status
+		if result.Reason != "" {
+			line += " (" + result.Reason + ")"
+		}
+		switch result.Status {
+		case "supported":
+			fmt.Println(greenText(line))
+			newestSupported = release // Releases are listed oldest first.
+		case "untested":
+			fmt.Println(yellowText(line))
+		default:
+			fmt.Println(redText(line))
+		}
+	}
+	return compatibility, newestSupported
+}
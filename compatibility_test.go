@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestCheckReleaseCompatibility(t *testing.T) {
+	ubuntu := func(version string) map[string]string {
+		return map[string]string{"ID": "ubuntu", "VERSION_ID": version}
+	}
+	rocky := func(version string) map[string]string { return map[string]string{"ID": "rocky", "VERSION_ID": version} }
+
+	tests := []struct {
+		release   string
+		osRelease map[string]string
+		glibc     string
+		want      string
+	}{
+		{"R2025a", ubuntu("24.04"), "2.39", "supported"},
+		{"R2025a", ubuntu("25.04"), "2.41", "untested"},
+		{"R2025a", ubuntu("20.04"), "2.31", "unsupported"},
+		{"R2024a", ubuntu("24.04"), "2.39", "untested"},
+		{"R2017b", ubuntu("24.04"), "2.39", "unsupported"},
+		{"R2019a", ubuntu("24.04"), "2.39", "unsupported"},
+		{"R2019a", ubuntu("20.04"), "2.31", "untested"},
+		{"R2022b", rocky("9.4"), "2.34", "supported"},
+		{"R2017b", rocky("8.10"), "2.28", "untested"},
+		{"R2017b", rocky("9.4"), "2.34", "unsupported"},
+		{"R2025a", rocky("7.9"), "2.17", "unsupported"},
+		{"R2025a", map[string]string{"ID": "arch"}, "2.41", "untested"},
+		{"R2025a", map[string]string{"ID": "arch"}, "2.17", "unsupported"},
+	}
+	for _, test := range tests {
+		got := checkReleaseCompatibility(test.release, test.osRelease, test.glibc)
+		if got.Status != test.want {
+			t.Errorf("%s on %s %s with glibc %s = %s (%s), want %s", test.release, test.osRelease["ID"], test.osRelease["VERSION_ID"], test.glibc, got.Status, got.Reason, test.want)
+		}
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version, minimum string
+		want             bool
+	}{
+		{"2.35", "2.28", true},
+		{"2.28", "2.28", true},
+		{"2.9", "2.17", false},
+		{"2.17.1", "2.17", true},
+		{"3", "2.40", true},
+	}
+	for _, test := range tests {
+		if got := versionAtLeast(test.version, test.minimum); got != test.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v, want %v", test.version, test.minimum, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	readline "github.com/Jestzer/readlineJestzer"
+	"github.com/fatih/color"
+)
+
+// Where MATLAB goes inside containers.
+const containerInstallRoot = "/opt/matlab"
+
+// The kinds of container recipes the export command can write.
+var containerFormats = []string{"dockerfile", "apptainer"}
+
+func isContainerFormat(format string) bool {
+	for _, containerFormat := range containerFormats {
+		if format == containerFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// The oldest Ubuntu we'll build on. 16.04 names a few packages differently (there's no libgl1, for example), so releases that
+// list it as their newest LTS get 18.04 instead, which they run on fine.
+const minimumContainerUbuntu = "18.04"
+
+// The newest Ubuntu LTS each release supports, which is what MathWorks builds its own images on.
+func containerBaseImage(release string) string {
+	baseVersion := minimumContainerUbuntu
+	for _, version := range linuxSupportTable[release].Ubuntu {
+		year, month, _ := strings.Cut(version, ".")
+		if yearNumber, err := strconv.Atoi(year); err == nil && yearNumber%2 == 0 && month == "04" { // LTS releases come out in April of even years.
+			if versionAtLeast(version, baseVersion) {
+				baseVersion = version
+			}
+		}
+	}
+	return "ubuntu:" + baseVersion
+}
+
+// The tools the recipe uses, plus the Debian packages for every library the release needs. Ubuntu 24.04 renamed a few of them.
+func containerPackages(release string) []string {
+	packages := []string{"ca-certificates", "wget", "unzip", "locales", "locales-all", "procps", "net-tools", "make", "sudo"}
+	version := strings.TrimPrefix(containerBaseImage(release), "ubuntu:")
+	renamed := debianUsesT64Names(map[string]string{"ID": "ubuntu", "VERSION_ID": version})
+	for _, library := range linuxLibrariesFor(release) {
+		name := library.Debian
+		if renamed && library.RenamedT64 {
+			name += "t64"
+		}
+		packages = appendUnique(packages, name)
+	}
+	return packages
+}
+
+// Everything a container recipe needs to know.
+type containerRecipe struct {
+	Release        string
+	Products       []string
+	LicenseSetting string // What MLM_LICENSE_FILE should be set to. Empty means it's left for whoever runs the container.
+	MPMSHA256      string // Empty if MPM couldn't be downloaded to hash it.
+}
+
+func (recipe containerRecipe) destination() string {
+	return containerInstallRoot + "/" + recipe.Release
+}
+
+// The shell commands that fetch MPM, check it, install the products, and clean up after themselves.
+func (recipe containerRecipe) installCommands() []string {
+	plan := installPlan{Release: recipe.Release, Destination: recipe.destination(), Products: recipe.Products}
+	return []string{
+		"wget -q " + mpmURLFor("linux") + " -O /tmp/mpm",
+		"if [ -n \"$MPM_SHA256\" ]; then echo \"$MPM_SHA256  /tmp/mpm\" | sha256sum -c -; fi",
+		"chmod +x /tmp/mpm",
+		strings.Join(plan.mpmArgs("/tmp/mpm"), " "),
+		"rm -rf /tmp/mpm /tmp/mathworks_*",
+		"ln -s " + recipe.destination() + "/bin/matlab /usr/local/bin/matlab",
+	}
+}
+
+func (recipe containerRecipe) dockerfile() string {
+	baseImage := containerBaseImage(recipe.Release)
+	var builder strings.Builder
+	builder.WriteString("# MATLAB " + recipe.Release + ", created by MPM.Go " + versionNumber + ".\n")
+	builder.WriteString("FROM " + baseImage + "\n\n")
+	builder.WriteString("ENV DEBIAN_FRONTEND=noninteractive\n")
+	builder.WriteString("RUN apt-get update && \\\n    apt-get install --no-install-recommends -y \\\n        " +
+		strings.Join(containerPackages(recipe.Release), " \\\n        ") + " && \\\n    apt-get clean && rm -rf /var/lib/apt/lists/*\n\n")
+	builder.WriteString("# MPM changes often. If the build fails the check below, update this hash or build with --build-arg MPM_SHA256=\n")
+	builder.WriteString("ARG MPM_SHA256=" + recipe.MPMSHA256 + "\n")
+	builder.WriteString("RUN " + strings.Join(recipe.installCommands(), " && \\\n    ") + "\n\n")
+	builder.WriteString("ENV PATH=" + recipe.destination() + "/bin:$PATH\n")
+	if recipe.LicenseSetting != "" {
+		builder.WriteString("ENV MLM_LICENSE_FILE=" + recipe.LicenseSetting + "\n")
+	} else {
+		builder.WriteString("# Set MLM_LICENSE_FILE to your license server when running the container, such as -e MLM_LICENSE_FILE=27000@licenseserver.\n")
+	}
+	builder.WriteString("ENTRYPOINT [\"matlab\"]\n")
+	return builder.String()
+}
+
+func (recipe containerRecipe) apptainerDefinition() string {
+	baseImage := containerBaseImage(recipe.Release)
+	var builder strings.Builder
+	builder.WriteString("Bootstrap: docker\nFrom: " + baseImage + "\n\n")
+	builder.WriteString("%labels\n    Description MATLAB " + recipe.Release + ", created by MPM.Go " + versionNumber + "\n\n")
+	builder.WriteString("%environment\n    export PATH=" + recipe.destination() + "/bin:$PATH\n")
+	if recipe.LicenseSetting != "" {
+		builder.WriteString("    export MLM_LICENSE_FILE=" + recipe.LicenseSetting + "\n")
+	}
+	builder.WriteString("\n%post\n    set -e\n    export DEBIAN_FRONTEND=noninteractive\n")
+	builder.WriteString("    apt-get update\n    apt-get install --no-install-recommends -y " + strings.Join(containerPackages(recipe.Release), " ") + "\n")
+	builder.WriteString("    apt-get clean && rm -rf /var/lib/apt/lists/*\n\n")
+	builder.WriteString("    # MPM changes often. If the check below fails, update this hash or clear it to skip the check.\n")
+	builder.WriteString("    MPM_SHA256=" + recipe.MPMSHA256 + "\n")
+	for _, command := range recipe.installCommands() {
+		builder.WriteString("    " + command + "\n")
+	}
+	builder.WriteString("\n%runscript\n    exec matlab \"$@\"\n")
+	return builder.String()
+}
+
+// Ask for anything the recipe still needs and write it. Returns where it was saved.
+func exportContainerRecipe(rl *readline.Instance, args []string, format string, release string, products []string) (string, error) {
+	redText := color.New(color.FgRed).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	recipe := containerRecipe{Release: release, Products: products}
+	for {
+		input := promptUser(rl, "Enter your license server as port@host to set MLM_LICENSE_FILE in the image. Press Enter to leave it to whoever runs the container.")
+		if input == "" {
+			break
+		}
+		servers, err := parseLicenseServers(input)
+		if err != nil {
+			fmt.Println(redText("Error: ", err))
+			continue
+		}
+		recipe.LicenseSetting = licenseServerSetting(servers)
+		break
+	}
+
+	// Pin the MPM we can see right now so the build notices if it's been tampered with.
+	fmt.Println("Downloading MPM to record its hash, please wait.")
+	tempDir, err := os.MkdirTemp("", "mpm-export")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+	if err := downloadFile(mpmURLFor("linux"), filepath.Join(tempDir, "mpm")); err != nil {
+		fmt.Println(yellowText("Could not download MPM, so its hash won't be checked during the build: ", err))
+	} else if recipe.MPMSHA256, err = hashFile(filepath.Join(tempDir, "mpm")); err != nil {
+		return "", err
+	}
+
+	contents, defaultName := recipe.dockerfile(), "Dockerfile"
+	if format == "apptainer" {
+		contents, defaultName = recipe.apptainerDefinition(), "matlab_"+release+".def"
+	}
+	return writeExport(rl, args, defaultName, contents, 0644)
+}
+
+// Save an exported file to --output or wherever you'd like it, asking before overwriting anything.
+func writeExport(rl *readline.Instance, args []string, defaultName string, contents string, mode os.FileMode) (string, error) {
+	outputPath := ""
+	if outputPaths := argumentValues(args, "--output"); len(outputPaths) > 0 {
+		outputPath = outputPaths[len(outputPaths)-1]
+	}
+	for outputPath == "" {
+		outputPath = promptUser(rl, "Enter the path where you would like to save the export. Press Enter to use \""+defaultName+"\"")
+		if outputPath == "" {
+			outputPath = defaultName
+		}
+		if _, err := os.Stat(outputPath); err == nil && !promptYesNo(rl, "\""+outputPath+"\" already exists. Would you like to overwrite it?") {
+			outputPath = ""
+		}
+	}
+	if err := os.WriteFile(outputPath, []byte(contents), mode); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
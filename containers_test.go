@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func containsValue(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestContainerBaseImage(t *testing.T) {
+	tests := map[string]string{
+		"R2017b": "ubuntu:18.04", // Its newest LTS is 16.04, which is older than we build on.
+		"R2018b": "ubuntu:18.04",
+		"R2022b": "ubuntu:22.04",
+		"R2025a": "ubuntu:24.04",
+		"R2099a": "ubuntu:18.04",
+	}
+	for release, want := range tests {
+		if got := containerBaseImage(release); got != want {
+			t.Errorf("containerBaseImage(%q) = %q, want %q", release, got, want)
+		}
+	}
+}
+
+func TestContainerPackagesForR2017b(t *testing.T) {
+	packages := containerPackages("R2017b")
+	for _, want := range []string{"wget", "libgl1", "libasound2", "libgtk-3-0"} {
+		if !containsValue(packages, want) {
+			t.Errorf("R2017b's packages are missing %s: %v", want, packages)
+		}
+	}
+	for _, unwanted := range []string{"libgbm1", "libwayland-client0", "libasound2t64"} {
+		if containsValue(packages, unwanted) {
+			t.Errorf("R2017b's packages shouldn't include %s", unwanted)
+		}
+	}
+
+	dockerfile := containerRecipe{Release: "R2017b", Products: []string{"MATLAB"}}.dockerfile()
+	if !strings.Contains(dockerfile, "FROM ubuntu:18.04\n") || !strings.Contains(dockerfile, " libgl1 ") {
+		t.Errorf("R2017b's Dockerfile doesn't install libgl1 on ubuntu:18.04:\n%s", dockerfile)
+	}
+}
+
+func TestContainerPackagesRenamedOnNoble(t *testing.T) {
+	packages := containerPackages("R2025a")
+	for _, want := range []string{"libasound2t64", "libgtk-3-0t64", "libgbm1", "libwayland-client0"} {
+		if !containsValue(packages, want) {
+			t.Errorf("R2025a's packages are missing %s: %v", want, packages)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Our MIME types for MATLAB's files. Shared between releases, so it's only removed once no release's menu entry is left.
+const desktopMimePackageName = "mpm-matlab.xml"
+
+const desktopMimeTypes = "text/x-matlab;application/x-matlab-livescript;application/x-simulink-model;"
+
+const desktopMimePackage = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Created by MPM.Go. -->
+<mime-info xmlns="http://www.freedesktop.org/standards/shared-mime-info">
+  <mime-type type="text/x-matlab">
+    <comment>MATLAB code</comment>
+    <glob pattern="*.m"/>
+  </mime-type>
+  <mime-type type="application/x-matlab-livescript">
+    <comment>MATLAB live script</comment>
+    <glob pattern="*.mlx"/>
+  </mime-type>
+  <mime-type type="application/x-simulink-model">
+    <comment>Simulink model</comment>
+    <glob pattern="*.slx"/>
+  </mime-type>
+</mime-info>
+`
+
+// Where menu entries and MIME types go. Everyone gets them when we're root, and only you do otherwise.
+func desktopDataDirectories(systemWide bool) (string, error) {
+	if systemWide {
+		return "/usr/share", nil
+	}
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return dataHome, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".local", "share"), nil
+}
+
+// The menu entry's file name. Each release gets its own so several can sit side by side.
+func desktopEntryName(release string) string {
+	return "matlab-" + strings.ToLower(release) + ".desktop"
+}
+
+// MATLAB's icon has moved around between releases, so use the first one we can find.
+func matlabIconPath(installPath string) string {
+	for _, candidate := range []string{
+		filepath.Join("bin", "glnxa64", "cef_resources", "matlab_icon.png"),
+		filepath.Join("toolbox", "shared", "dastudio", "resources", "MatlabIcon.png"),
+		filepath.Join("toolbox", "matlab", "icons", "matlabicon.gif"),
+	} {
+		iconPath := filepath.Join(installPath, candidate)
+		if _, err := os.Stat(iconPath); err == nil {
+			return iconPath
+		}
+	}
+	return ""
+}
+
+// Add MATLAB to the application menu and let it open .m, .mlx, and .slx files. Returns the files that were created.
+func createDesktopEntry(installPath string, release string) ([]string, error) {
+	dataDirectory, err := desktopDataDirectories(os.Geteuid() == 0)
+	if err != nil {
+		return nil, err
+	}
+	applicationsDirectory := filepath.Join(dataDirectory, "applications")
+	mimePackagesDirectory := filepath.Join(dataDirectory, "mime", "packages")
+	for _, directory := range []string{applicationsDirectory, mimePackagesDirectory} {
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := []string{
+		"[Desktop Entry]",
+		"Type=Application",
+		"Name=MATLAB " + release,
+		"GenericName=MATLAB",
+		"Comment=Created by MPM.Go",
+		"Exec=\"" + filepath.Join(installPath, "bin", "matlab") + "\" -desktop %F",
+		"Terminal=false",
+		"Categories=Development;Education;Science;Math;",
+		"MimeType=" + desktopMimeTypes,
+		"StartupNotify=true",
+	}
+	if iconPath := matlabIconPath(installPath); iconPath != "" {
+		entry = append(entry, "Icon="+iconPath)
+	}
+
+	entryPath := filepath.Join(applicationsDirectory, desktopEntryName(release))
+	if err := os.WriteFile(entryPath, []byte(strings.Join(entry, "\n")+"\n"), 0644); err != nil {
+		return nil, err
+	}
+	mimePath := filepath.Join(mimePackagesDirectory, desktopMimePackageName)
+	if err := os.WriteFile(mimePath, []byte(desktopMimePackage), 0644); err != nil {
+		return []string{entryPath}, err
+	}
+
+	refreshDesktopDatabases(dataDirectory)
+	return []string{entryPath, mimePath}, nil
+}
+
+// Remove the menu entry for an installation, wherever it was put. The MIME types go too once no other release is using them.
+// Returns the files that were removed.
+func removeDesktopEntry(installPath string, release string) []string {
+	var removed []string
+	for _, systemWide := range []bool{true, false} {
+		dataDirectory, err := desktopDataDirectories(systemWide)
+		if err != nil {
+			continue
+		}
+		applicationsDirectory := filepath.Join(dataDirectory, "applications")
+
+		// Only remove the entry if it's ours and it's for this installation, not another copy of the same release.
+		entryPath := filepath.Join(applicationsDirectory, desktopEntryName(release))
+		data, err := os.ReadFile(entryPath)
+		if err != nil || !strings.Contains(string(data), "Comment=Created by MPM.Go") || !strings.Contains(string(data), filepath.Join(installPath, "bin", "matlab")) {
+			continue
+		}
+		if err := os.Remove(entryPath); err != nil {
+			continue
+		}
+		removed = append(removed, entryPath)
+
+		if others, _ := filepath.Glob(filepath.Join(applicationsDirectory, "matlab-r*.desktop")); len(others) == 0 {
+			mimePath := filepath.Join(dataDirectory, "mime", "packages", desktopMimePackageName)
+			if err := os.Remove(mimePath); err == nil {
+				removed = append(removed, mimePath)
+			}
+		}
+		refreshDesktopDatabases(dataDirectory)
+	}
+	return removed
+}
+
+// Let the desktop know things changed. These tools aren't installed everywhere, and the desktop will catch up eventually without them.
+func refreshDesktopDatabases(dataDirectory string) {
+	for _, command := range [][]string{
+		{"update-desktop-database", filepath.Join(dataDirectory, "applications")},
+		{"update-mime-database", filepath.Join(dataDirectory, "mime")},
+	} {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		if output, err := exec.Command(command[0], command[1:]...).CombinedOutput(); err != nil {
+			fmt.Println("Could not run " + command[0] + ": " + strings.TrimSpace(string(output)))
+		}
+	}
+}
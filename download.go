@@ -0,0 +1,293 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	readline "github.com/Jestzer/readlineJestzer"
+	"github.com/fatih/color"
+)
+
+// Describes what's inside an offline bundle so it can be checked before installing from it.
+type bundleManifest struct {
+	WrapperVersion string       `json:"wrapperVersion"`
+	Created        string       `json:"created"`
+	Release        string       `json:"release"`
+	Platform       string       `json:"platform"`
+	Products       []string     `json:"products"`
+	MPM            string       `json:"mpm"`
+	Files          []bundleFile `json:"files"`
+}
+
+// A single file in an offline bundle. Paths are relative to the bundle and always use forward slashes.
+type bundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+const bundleManifestName = "manifest.json"
+
+// Where MPM puts the files it downloads inside a bundle.
+const bundleArchivesDirectory = "archives"
+
+// The names MPM uses for each platform.
+func mpmArchitecture(platform string) string {
+	switch platform {
+	case "linux":
+		return "glnxa64"
+	case "windows":
+		return "win64"
+	case "macOSx64":
+		return "maci64"
+	case "macOSARM":
+		return "maca64"
+	}
+	return ""
+}
+
+// Where to download MPM for a given platform.
+func mpmURLFor(platform string) string {
+	return "https://www.mathworks.com/mpm/" + mpmArchitecture(platform) + "/mpm"
+}
+
+// MPM is called mpm.exe on Windows and mpm everywhere else.
+func mpmExecutableName(platform string) string {
+	if platform == "windows" {
+		return "mpm.exe"
+	}
+	return "mpm"
+}
+
+// Ask which platform the products are being downloaded for. This doesn't need to match the machine you're on.
+func promptTargetPlatform(rl *readline.Instance, platform string) string {
+	redText := color.New(color.FgRed).SprintFunc()
+	validPlatforms := []string{"linux", "windows", "macOSx64", "macOSARM"}
+	for {
+		answer := promptUser(rl, "Enter the platform you would like to download products for: linux, windows, macOSx64, or macOSARM. "+
+			"Press Enter to use \""+platform+"\"")
+		if answer == "" {
+			return platform
+		}
+		for _, validPlatform := range validPlatforms {
+			if strings.EqualFold(answer, validPlatform) {
+				return validPlatform
+			}
+		}
+		fmt.Println(redText("Invalid platform. Enter either linux, windows, macOSx64, or macOSARM."))
+	}
+}
+
+// Download the installation files for a release without installing them so they can be carried to machines without internet access.
+// The bundle gets the files MPM downloads, a copy of MPM for the target platform, and a manifest listing everything with its hash.
+func createOfflineBundle(rl *readline.Instance, mpmFullPath string, targetPlatform string, release string, products []string) error {
+	redText := color.New(color.FgRed).SprintFunc()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = "."
+	}
+	defaultBundlePath := filepath.Join(cwd, "MPM_bundle_"+release+"_"+mpmArchitecture(targetPlatform))
+
+	var bundlePath string
+	for {
+		bundlePath = promptUser(rl, "Enter the path where you would like to save the offline bundle. Press Enter to use \""+defaultBundlePath+"\"")
+		if bundlePath == "" {
+			bundlePath = defaultBundlePath
+		}
+		if _, err := os.Stat(filepath.Join(bundlePath, bundleManifestName)); err == nil {
+			if !promptYesNo(rl, "An offline bundle already exists in \""+bundlePath+"\". Would you like to overwrite it?") {
+				continue
+			}
+			// Start from nothing so archives from the old bundle don't end up in the new one's manifest.
+			if err := os.RemoveAll(filepath.Join(bundlePath, bundleArchivesDirectory)); err != nil {
+				fmt.Println(redText("Error removing the old bundle's files: ", err, " Please pick a different path."))
+				continue
+			}
+		} else if entries, err := os.ReadDir(bundlePath); err == nil && len(entries) > 0 {
+			fmt.Println(redText("\"" + bundlePath + "\" isn't empty and isn't an offline bundle. Please pick an empty or new directory."))
+			continue
+		}
+		if err := os.MkdirAll(bundlePath, 0755); err != nil {
+			fmt.Println(redText("Error creating directory: ", err, " Please pick a different path."))
+			continue
+		}
+		break
+	}
+
+	// Let MPM do the heavy lifting.
+	cmdArgs := []string{
+		mpmFullPath,
+		"download",
+		"--release=" + release,
+		"--destination=" + filepath.Join(bundlePath, bundleArchivesDirectory),
+		"--platforms=" + mpmArchitecture(targetPlatform),
+		"--products",
+	}
+	cmdArgs = append(cmdArgs, products...)
+
+	fmt.Println("Downloading products, please wait.")
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	cmd.Stdout = &customWriter{writer: os.Stdout}
+	cmd.Stderr = &customWriter{writer: os.Stderr}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("MPM could not download the products: %w", err)
+	}
+
+	// The machine using the bundle won't be able to download MPM itself, so include a copy for its platform.
+	mpmName := mpmExecutableName(targetPlatform)
+	fmt.Println("Downloading MPM for " + targetPlatform + ". Please wait.")
+	if err := downloadFile(mpmURLFor(targetPlatform), filepath.Join(bundlePath, mpmName)); err != nil {
+		return fmt.Errorf("could not download MPM for %s: %w", targetPlatform, err)
+	}
+	if targetPlatform != "windows" {
+		if err := os.Chmod(filepath.Join(bundlePath, mpmName), 0755); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Writing the bundle's manifest, please wait.")
+	manifest := bundleManifest{
+		WrapperVersion: versionNumber,
+		Created:        time.Now().UTC().Format(time.RFC3339),
+		Release:        release,
+		Platform:       targetPlatform,
+		Products:       products,
+		MPM:            mpmName,
+	}
+	if err := writeBundleManifest(bundlePath, manifest); err != nil {
+		return err
+	}
+
+	fmt.Println("Your offline bundle has been saved to \"" + bundlePath + "\".")
+	return nil
+}
+
+// Hash what this download wrote (MPM's archives and the copy of MPM) and save the manifest alongside them. Anything else in the
+// folder is left out so the manifest never vouches for files we didn't put there.
+func writeBundleManifest(bundlePath string, manifest bundleManifest) error {
+	manifest.Files = nil
+	addFile := func(path string) error {
+		relativePath, err := filepath.Rel(bundlePath, path)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, bundleFile{Path: filepath.ToSlash(relativePath), Size: info.Size(), SHA256: hash})
+		return nil
+	}
+
+	err := filepath.WalkDir(filepath.Join(bundlePath, bundleArchivesDirectory), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return addFile(path)
+	})
+	if err == nil {
+		err = addFile(filepath.Join(bundlePath, manifest.MPM))
+	}
+	if err != nil {
+		return fmt.Errorf("could not hash the bundle's files: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(bundlePath, bundleManifestName), data, 0644)
+}
+
+// The SHA-256 hash of a file, as hex.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Read an offline bundle's manifest and make sure the bundle is complete, undamaged, and meant for this platform.
+func loadOfflineBundle(bundlePath string, platform string) (bundleManifest, error) {
+	var manifest bundleManifest
+
+	data, err := os.ReadFile(filepath.Join(bundlePath, bundleManifestName))
+	if err != nil {
+		return manifest, fmt.Errorf("could not read the bundle's manifest. Make sure this directory was made with the \"download\" command: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("the bundle's manifest is damaged: %w", err)
+	}
+
+	if manifest.Platform != platform {
+		return manifest, fmt.Errorf("this bundle is for %s, but you're installing for %s", manifest.Platform, platform)
+	}
+
+	validRelease := false
+	for _, release := range validReleasesFor(platform) {
+		if release == manifest.Release {
+			validRelease = true
+			break
+		}
+	}
+	if !validRelease {
+		return manifest, fmt.Errorf("this bundle is for %s, which can't be installed on %s", manifest.Release, platform)
+	}
+
+	if missingProducts := checkProductsExist(manifest.Products, productsForRelease(platform, manifest.Release)); len(missingProducts) > 0 {
+		return manifest, fmt.Errorf("this bundle lists products that don't exist in %s: %s", manifest.Release, strings.Join(missingProducts, ", "))
+	}
+
+	// Files get damaged on their way to offline machines more often than you'd think.
+	fmt.Println("Checking the bundle's files, please wait.")
+	mpmListed := false
+	for _, file := range manifest.Files {
+		if file.Path == manifest.MPM {
+			mpmListed = true
+		}
+		fullPath := filepath.Join(bundlePath, filepath.FromSlash(file.Path))
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return manifest, fmt.Errorf("the bundle is missing %s", file.Path)
+		}
+		if info.Size() != file.Size {
+			return manifest, fmt.Errorf("%s is %d bytes, but should be %d bytes", file.Path, info.Size(), file.Size)
+		}
+		hash, err := hashFile(fullPath)
+		if err != nil {
+			return manifest, err
+		}
+		if hash != file.SHA256 {
+			return manifest, fmt.Errorf("%s is damaged. Its hash does not match the manifest", file.Path)
+		}
+	}
+	if manifest.MPM == "" || !mpmListed {
+		return manifest, fmt.Errorf("the bundle does not include a copy of MPM")
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteBundleManifestOnlyListsDownloadedFiles(t *testing.T) {
+	bundlePath := t.TempDir()
+	files := map[string]string{
+		"archives/products.zip": "archive",
+		"mpm":                   "mpm",
+		"notes.txt":             "not ours",
+		"old/stale.zip":         "not ours either",
+	}
+	for name, contents := range files {
+		path := filepath.Join(bundlePath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writeBundleManifest(bundlePath, bundleManifest{MPM: "mpm"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(bundlePath, bundleManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest bundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Files) != 2 || manifest.Files[0].Path != "archives/products.zip" || manifest.Files[1].Path != "mpm" {
+		t.Errorf("files = %+v", manifest.Files)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Details about an installation of MathWorks products that already exists on disk.
+type installation struct {
+	Path     string
+	Release  string
+	Update   string
+	Products []string
+}
+
+// The parts of VersionInfo.xml we care about. Every installation has one of these in its root folder.
+type versionInfo struct {
+	Release     string `xml:"release"`
+	Description string `xml:"description"`
+}
+
+// Folders inside "toolbox" that belong to a product. These are only used to estimate how much space a product takes up,
+// so products missing from here just won't be included in the estimate.
+var productFolders = map[string][]string{
+	"Aerospace_Toolbox":                       {"aero"},
+	"Antenna_Toolbox":                         {"antenna"},
+	"Audio_Toolbox":                           {"audio"},
+	"Bioinformatics_Toolbox":                  {"bioinfo"},
+	"Communications_Toolbox":                  {"comm"},
+	"Computer_Vision_Toolbox":                 {"vision"},
+	"Control_System_Toolbox":                  {"control"},
+	"Curve_Fitting_Toolbox":                   {"curvefit"},
+	"Data_Acquisition_Toolbox":                {"daq"},
+	"Database_Toolbox":                        {"database"},
+	"Datafeed_Toolbox":                        {"datafeed"},
+	"Deep_Learning_Toolbox":                   {"nnet"},
+	"DSP_System_Toolbox":                      {"dsp"},
+	"Econometrics_Toolbox":                    {"econ"},
+	"Embedded_Coder":                          {"ecoder"},
+	"Financial_Instruments_Toolbox":           {"fininst"},
+	"Financial_Toolbox":                       {"finance"},
+	"Fixed-Point_Designer":                    {"fixedpoint"},
+	"Fuzzy_Logic_Toolbox":                     {"fuzzy"},
+	"Global_Optimization_Toolbox":             {"globaloptim"},
+	"GPU_Coder":                               {"gpucoder"},
+	"HDL_Coder":                               {"hdlcoder"},
+	"Image_Acquisition_Toolbox":               {"imaq"},
+	"Image_Processing_Toolbox":                {"images"},
+	"Instrument_Control_Toolbox":              {"instrument"},
+	"Lidar_Toolbox":                           {"lidar"},
+	"LTE_Toolbox":                             {"lte"},
+	"Mapping_Toolbox":                         {"map"},
+	"MATLAB_Coder":                            {"coder"},
+	"MATLAB_Compiler":                         {"compiler"},
+	"MATLAB_Compiler_SDK":                     {"compiler_sdk"},
+	"Model_Predictive_Control_Toolbox":        {"mpc"},
+	"Navigation_Toolbox":                      {"nav"},
+	"Optimization_Toolbox":                    {"optim"},
+	"Parallel_Computing_Toolbox":              {"parallel"},
+	"Partial_Differential_Equation_Toolbox":   {"pde"},
+	"Phased_Array_System_Toolbox":             {"phased"},
+	"Predictive_Maintenance_Toolbox":          {"predmaint"},
+	"Reinforcement_Learning_Toolbox":          {"rl"},
+	"RF_Toolbox":                              {"rf"},
+	"Risk_Management_Toolbox":                 {"risk"},
+	"Robotics_System_Toolbox":                 {"robotics"},
+	"Robust_Control_Toolbox":                  {"robust"},
+	"ROS_Toolbox":                             {"ros"},
+	"Sensor_Fusion_and_Tracking_Toolbox":      {"fusion"},
+	"Signal_Processing_Toolbox":               {"signal"},
+	"SimBiology":                              {"simbio"},
+	"Simscape":                                {"simscape"},
+	"Simulink":                                {"simulink"},
+	"Simulink_Coder":                          {"rtw"},
+	"Stateflow":                               {"stateflow"},
+	"Statistics_and_Machine_Learning_Toolbox": {"stats"},
+	"Symbolic_Math_Toolbox":                   {"symbolic"},
+	"System_Identification_Toolbox":           {"ident"},
+	"Text_Analytics_Toolbox":                  {"textanalytics"},
+	"Vehicle_Network_Toolbox":                 {"vnt"},
+	"Wavelet_Toolbox":                         {"wavelet"},
+	"WLAN_Toolbox":                            {"wlan"},
+	"5G_Toolbox":                              {"5g"},
+}
+
+// Where installations normally end up for each platform. These match the default installation paths the wizard offers.
+func defaultInstallationRoots(platform string) []string {
+	switch platform {
+	case "macOSx64", "macOSARM", "darwin":
+		return []string{"/Applications/MATLAB_*.app", "/Applications/Polyspace_*.app"}
+	case "windows":
+		return []string{`C:\Program Files\MATLAB\*`, `C:\Program Files\Polyspace\*`}
+	case "linux":
+		return []string{"/usr/local/MATLAB/*", "/usr/local/Polyspace/*"}
+	}
+	return nil
+}
+
+// Look for installations in the given paths. Glob patterns are allowed. Anything without a VersionInfo.xml isn't an installation, so it's skipped.
+func findInstallations(patterns []string) []installation {
+	var installations []installation
+	seen := make(map[string]struct{})
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			fullPath, err := filepath.Abs(match)
+			if err != nil {
+				continue
+			}
+
+			// Symlinks such as a "current" link would otherwise show the same installation twice.
+			if resolvedPath, err := filepath.EvalSymlinks(fullPath); err == nil {
+				fullPath = resolvedPath
+			}
+			if _, exists := seen[fullPath]; exists {
+				continue
+			}
+
+			found, err := readInstallation(fullPath)
+			if err != nil {
+				continue
+			}
+			seen[fullPath] = struct{}{}
+			installations = append(installations, found)
+		}
+	}
+
+	sort.Slice(installations, func(i, j int) bool {
+		if installations[i].Release == installations[j].Release {
+			return installations[i].Path < installations[j].Path
+		}
+		return installations[i].Release < installations[j].Release
+	})
+	return installations
+}
+
+// Read the release, update level, and products of the installation in installPath.
+func readInstallation(installPath string) (installation, error) {
+	found := installation{Path: installPath}
+
+	data, err := os.ReadFile(filepath.Join(installPath, "VersionInfo.xml"))
+	if err != nil {
+		return found, err
+	}
+
+	var info versionInfo
+	if err := xml.Unmarshal(data, &info); err != nil {
+		return found, fmt.Errorf("could not read VersionInfo.xml: %w", err)
+	}
+	found.Release = strings.TrimSpace(info.Release)
+	found.Update = strings.TrimSpace(info.Description)
+	if found.Release == "" {
+		return found, fmt.Errorf("no release is listed in VersionInfo.xml")
+	}
+
+	found.Products, err = installedProducts(installPath)
+	if err != nil {
+		return found, err
+	}
+	return found, nil
+}
+
+// Every installed product leaves a file behind in appdata/products named something like "Parallel Computing Toolbox 24.1.0.2537033.xml".
+// Turn those back into the names MPM uses.
+func installedProducts(installPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(installPath, "appdata", "products"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var products []string
+	seen := make(map[string]struct{})
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(name), ".xml") {
+			continue
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+
+		// Drop the version number at the end.
+		if lastSpace := strings.LastIndex(name, " "); lastSpace > 0 {
+			version := name[lastSpace+1:]
+			if version != "" && unicode.IsDigit(rune(version[0])) {
+				name = name[:lastSpace]
+			}
+		}
+
+		product := strings.ReplaceAll(strings.TrimSpace(name), " ", "_")
+		if product == "" {
+			continue
+		}
+		if _, exists := seen[product]; exists {
+			continue
+		}
+		seen[product] = struct{}{}
+		products = append(products, product)
+	}
+
+	sort.Strings(products)
+	return products, nil
+}
+
+// Add up the size of every file in a directory.
+func directorySize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Rough size of the given products inside an installation. The second value lists the products we couldn't estimate.
+func estimateProductsSize(installPath string, products []string) (int64, []string) {
+	var size int64
+	var unknownProducts []string
+	for _, product := range products {
+		folders, exists := productFolders[product]
+		if !exists {
+			unknownProducts = append(unknownProducts, product)
+			continue
+		}
+		for _, folder := range folders {
+			folderSize, err := directorySize(filepath.Join(installPath, "toolbox", folder))
+			if err != nil {
+				continue
+			}
+			size += folderSize
+		}
+	}
+	return size, unknownProducts
+}
+
+// Make byte counts readable for humans.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	divisor, exponent := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		divisor *= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(divisor), "KMGTPE"[exponent])
+}
@@ -0,0 +1,812 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	readline "github.com/Jestzer/readlineJestzer"
+	"github.com/fatih/color"
+)
+
+// How close to expiring a feature needs to be before we warn about it.
+const licenseExpiryWarningDays = 30
+
+// A SERVER line. Port is 0 when none is given, in which case FlexNet picks one from 27000-27009.
+type licenseServer struct {
+	Host   string
+	HostID string
+	Port   int
+	Line   int
+}
+
+// A DAEMON or VENDOR line. Port is 0 when none is given.
+type licenseDaemon struct {
+	Name string
+	Port int
+	Line int
+}
+
+// An INCREMENT or FEATURE line.
+type licenseFeature struct {
+	Name      string
+	Vendor    string
+	Version   string
+	Expiry    time.Time
+	Permanent bool
+	Count     string // Either a number or "uncounted".
+	Options   map[string]string
+	Raw       string // The whole line, continuations included.
+	Line      int
+}
+
+// Everything we could make out of a license file, plus anything wrong with it.
+type licenseFile struct {
+	Path      string
+	Servers   []licenseServer
+	Daemons   []licenseDaemon
+	UseServer bool
+	Features  []licenseFeature
+	Errors    []string // Problems that will stop the license from working.
+	Warnings  []string // Problems you should know about, but that might not matter.
+
+	// Set when licenses were merged and one of them is a client network license, meaning anything not covered by the
+	// features we can see might still come from the license server.
+	ServerBacked bool
+}
+
+// Lines in a FlexNet license file have to start with one of these.
+var licenseKeywords = map[string]struct{}{
+	"SERVER":     {},
+	"DAEMON":     {},
+	"VENDOR":     {},
+	"USE_SERVER": {},
+	"INCREMENT":  {},
+	"FEATURE":    {},
+	"PACKAGE":    {},
+	"UPGRADE":    {},
+	"FEATURESET": {},
+}
+
+// Does this license talk to a license server rather than being used directly?
+func (lic licenseFile) isNetworkLicense() bool {
+	return len(lic.Servers) > 0
+}
+
+// Is this a client's network license, which points at a license server without listing the features the server has?
+func (lic licenseFile) isClientNetworkLicense() bool {
+	return len(lic.Servers) > 0 && len(lic.Features) == 0
+}
+
+// Show everything wrong with a license file. Returns false if it has problems that will stop it from working.
+func printLicenseProblems(lic licenseFile) bool {
+	redText := color.New(color.FgRed).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	if len(lic.Errors) > 0 {
+		fmt.Println(redText("The license file \"" + lic.Path + "\" has the following problems:"))
+		for _, problem := range lic.Errors {
+			fmt.Println(redText("- " + problem))
+		}
+	}
+	if len(lic.Warnings) > 0 {
+		fmt.Println(yellowText("Warnings for the license file \"" + lic.Path + "\":"))
+		for _, warning := range lic.Warnings {
+			fmt.Println(yellowText("- " + warning))
+		}
+	}
+	return len(lic.Errors) == 0
+}
+
+// Read a license file and check it for anything that would stop it from working on this machine.
+// The error is only for files that can't be read at all. Everything else ends up in Errors and Warnings.
+// Host IDs are only checked if the license is for this machine, which it isn't when exporting for another one.
+func validateLicenseFile(licensePath string, thisMachine bool) (licenseFile, error) {
+	data, err := os.ReadFile(licensePath)
+	if err != nil {
+		return licenseFile{Path: licensePath}, err
+	}
+
+	// MathWorks also hands out XML licenses. We can't read those, but we can at least make sure they're intact.
+	if strings.EqualFold(filepath.Ext(licensePath), ".xml") {
+		lic := licenseFile{Path: licensePath}
+		decoder := xml.NewDecoder(strings.NewReader(string(data)))
+		for {
+			_, err := decoder.Token()
+			if err != nil {
+				if err != io.EOF {
+					lic.Errors = append(lic.Errors, "The XML in this license file is damaged: "+err.Error())
+				}
+				break
+			}
+		}
+		return lic, nil
+	}
+
+	lic := parseLicense(string(data), licensePath)
+	checkLicenseExpiry(&lic, time.Now())
+	if thisMachine {
+		checkLicenseHostIDs(&lic)
+	}
+	return lic, nil
+}
+
+// Parse the contents of a FlexNet license file. Syntax problems are recorded in the result instead of stopping the parse,
+// so you get to see all of them at once.
+func parseLicense(contents string, licensePath string) licenseFile {
+	lic := licenseFile{Path: licensePath}
+
+	// Carriage returns usually mean the file was edited or emailed on Windows. The license manager on Linux and macOS chokes on them.
+	if strings.Contains(contents, "\r\n") && runtime.GOOS != "windows" {
+		lic.Errors = append(lic.Errors, "This license file has Windows line endings. Save it with Unix line endings (or re-download it) before using it.")
+	}
+	if strings.Contains(strings.ReplaceAll(contents, "\r\n", "\n"), "\r") {
+		lic.Errors = append(lic.Errors, "This license file contains stray carriage returns. It was likely damaged while being copied or emailed.")
+	}
+	contents = strings.ReplaceAll(contents, "\r", "")
+
+	// Email clients love to "help" by swapping in fancy characters and HTML.
+	for lineNumber, line := range strings.Split(contents, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, character := range line {
+			if character > unicode.MaxASCII {
+				lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d contains the character %q, which doesn't belong in a license file. It was likely added by an email client or word processor.", lineNumber+1, character))
+				break
+			}
+		}
+		lowerLine := strings.ToLower(line)
+		if strings.Contains(lowerLine, "<br") || strings.Contains(lowerLine, "<div") || strings.Contains(lowerLine, "&nbsp;") || strings.Contains(line, "=3D") {
+			lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d contains HTML or email encoding. Save the license file directly instead of copying it out of an email.", lineNumber+1))
+		}
+	}
+
+	// Join continued lines together, keeping track of where each one started.
+	type logicalLine struct {
+		text   string
+		number int
+	}
+	var lines []logicalLine
+	continuing := false
+
+	// The newline at the end of the file doesn't start another line, so it can't finish a continuation either.
+	for lineNumber, line := range strings.Split(strings.TrimRight(contents, "\n"), "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		continues := strings.HasSuffix(trimmedLine, "\\")
+		if continues && line != strings.TrimRight(line, " \t") {
+			lic.Warnings = append(lic.Warnings, fmt.Sprintf("Line %d has spaces after its line continuation (\\). Some license managers don't allow this.", lineNumber+1))
+		}
+		trimmedLine = strings.TrimSuffix(trimmedLine, "\\")
+
+		if continuing {
+			lines[len(lines)-1].text += " " + trimmedLine
+		} else if trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") {
+			lines = append(lines, logicalLine{text: trimmedLine, number: lineNumber + 1})
+		} else {
+			continues = false // Comments and blank lines can't be continued.
+		}
+		continuing = continues
+	}
+	if continuing {
+		lic.Errors = append(lic.Errors, "The last line of this license file ends with a line continuation (\\). The file may have been cut off.")
+	}
+
+	for _, line := range lines {
+		fields := splitLicenseFields(line.text)
+		keyword := strings.ToUpper(fields[0])
+		if _, known := licenseKeywords[keyword]; !known {
+			lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d starts with \"%s\", which isn't a license file keyword. The line before it may have been wrapped by an email client.", line.number, fields[0]))
+			continue
+		}
+
+		switch keyword {
+		case "SERVER":
+			if len(fields) < 3 {
+				lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d: SERVER lines need a hostname and a host ID.", line.number))
+				continue
+			}
+			server := licenseServer{Host: fields[1], HostID: fields[2], Line: line.number}
+			if len(fields) > 3 && !strings.Contains(fields[3], "=") {
+				port, err := strconv.Atoi(fields[3])
+				if err != nil || port < 1 || port > 65535 {
+					lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d: \"%s\" is not a valid port number.", line.number, fields[3]))
+					continue
+				}
+				server.Port = port
+			}
+			lic.Servers = append(lic.Servers, server)
+
+		case "DAEMON", "VENDOR":
+			if len(fields) < 2 {
+				lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d: %s lines need a vendor daemon name.", line.number, keyword))
+				continue
+			}
+			daemon := licenseDaemon{Name: fields[1], Line: line.number}
+			for _, field := range fields[2:] {
+				key, value, found := strings.Cut(field, "=")
+				if found && strings.EqualFold(key, "port") {
+					port, err := strconv.Atoi(value)
+					if err != nil || port < 1 || port > 65535 {
+						lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d: \"%s\" is not a valid port number.", line.number, value))
+						continue
+					}
+					daemon.Port = port
+				}
+			}
+			lic.Daemons = append(lic.Daemons, daemon)
+
+		case "USE_SERVER":
+			lic.UseServer = true
+
+		case "INCREMENT", "FEATURE":
+			feature, err := parseLicenseFeature(fields)
+			if err != nil {
+				lic.Errors = append(lic.Errors, fmt.Sprintf("Line %d: %s.", line.number, err.Error()))
+				continue
+			}
+			feature.Raw = line.text
+			feature.Line = line.number
+			lic.Features = append(lic.Features, feature)
+		}
+	}
+
+	if len(lic.Features) == 0 && len(lic.Servers) == 0 {
+		lic.Errors = append(lic.Errors, "This license file doesn't contain any licenses or license servers.")
+	}
+	if lic.UseServer && len(lic.Servers) == 0 {
+		lic.Errors = append(lic.Errors, "This license file says to use a license server (USE_SERVER), but doesn't list one on a SERVER line.")
+	}
+	if len(lic.Servers) > 0 && len(lic.Features) > 0 && len(lic.Daemons) == 0 {
+		lic.Errors = append(lic.Errors, "This license file lists a license server and features, but no DAEMON line for the license manager to start.")
+	}
+	if len(lic.Servers) == 2 || len(lic.Servers) > 3 {
+		lic.Warnings = append(lic.Warnings, fmt.Sprintf("This license file lists %d SERVER lines. License servers are either used on their own or in groups of three.", len(lic.Servers)))
+	}
+
+	return lic
+}
+
+// INCREMENT feature vendor version expiry count [options...]
+func parseLicenseFeature(fields []string) (licenseFeature, error) {
+	keyword := strings.ToUpper(fields[0])
+	if len(fields) < 6 {
+		return licenseFeature{}, fmt.Errorf("%s lines need a feature name, vendor, version, expiration date, and license count. This line may have been cut off", keyword)
+	}
+
+	feature := licenseFeature{
+		Name:    fields[1],
+		Vendor:  fields[2],
+		Version: fields[3],
+		Count:   fields[5],
+		Options: make(map[string]string),
+	}
+
+	expiry, permanent, err := parseLicenseDate(fields[4])
+	if err != nil {
+		return feature, fmt.Errorf("\"%s\" is not a valid expiration date for %s", fields[4], feature.Name)
+	}
+	feature.Expiry = expiry
+	feature.Permanent = permanent
+
+	if !strings.EqualFold(feature.Count, "uncounted") {
+		if _, err := strconv.Atoi(feature.Count); err != nil {
+			return feature, fmt.Errorf("\"%s\" is not a valid license count for %s", feature.Count, feature.Name)
+		}
+	}
+
+	for _, field := range fields[6:] {
+		key, value, found := strings.Cut(field, "=")
+		if found {
+			feature.Options[strings.ToUpper(key)] = strings.Trim(value, "\"")
+		}
+	}
+	return feature, nil
+}
+
+// FlexNet dates look like 31-dec-2025. "permanent" and year 0 mean the feature never expires.
+func parseLicenseDate(date string) (time.Time, bool, error) {
+	if strings.EqualFold(date, "permanent") {
+		return time.Time{}, true, nil
+	}
+	parts := strings.Split(date, "-")
+	if len(parts) != 3 || parts[1] == "" {
+		return time.Time{}, false, fmt.Errorf("invalid date")
+	}
+	year, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if year == 0 {
+		return time.Time{}, true, nil
+	}
+	month := strings.ToUpper(parts[1][:1]) + strings.ToLower(parts[1][1:])
+	expiry, err := time.Parse("2-Jan-2006", parts[0]+"-"+month+"-"+parts[2])
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return expiry, false, nil
+}
+
+// Split a license line on whitespace, keeping quoted values such as VENDOR_STRING="a b c" together.
+func splitLicenseFields(line string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuotes := false
+	for _, character := range line {
+		switch {
+		case character == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(character)
+		case unicode.IsSpace(character) && !inQuotes:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(character)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// Warn about features that have expired or will soon. Features with the same expiration date are grouped so you don't get a wall of text.
+func checkLicenseExpiry(lic *licenseFile, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	soon := today.AddDate(0, 0, licenseExpiryWarningDays)
+
+	expired := make(map[time.Time][]string)
+	expiringSoon := make(map[time.Time][]string)
+	for _, feature := range lic.Features {
+		if feature.Permanent {
+			continue
+		}
+		if feature.Expiry.Before(today) {
+			expired[feature.Expiry] = appendUnique(expired[feature.Expiry], feature.Name)
+		} else if feature.Expiry.Before(soon) {
+			expiringSoon[feature.Expiry] = appendUnique(expiringSoon[feature.Expiry], feature.Name)
+		}
+	}
+
+	for _, date := range sortedDates(expired) {
+		lic.Warnings = append(lic.Warnings, fmt.Sprintf("Expired on %s: %s", date.Format("2-Jan-2006"), strings.Join(expired[date], ", ")))
+	}
+	for _, date := range sortedDates(expiringSoon) {
+		lic.Warnings = append(lic.Warnings, fmt.Sprintf("Expires on %s (in %d days): %s", date.Format("2-Jan-2006"), int(date.Sub(today).Hours()/24), strings.Join(expiringSoon[date], ", ")))
+	}
+
+	if len(lic.Features) > 0 {
+		allExpired := true
+		for _, feature := range lic.Features {
+			if feature.Permanent || !feature.Expiry.Before(today) {
+				allExpired = false
+				break
+			}
+		}
+		if allExpired {
+			lic.Errors = append(lic.Errors, "Every feature in this license file has expired.")
+		}
+	}
+}
+
+// Make sure the license is actually meant for this machine. Node-locked features are tied to a MAC address, hostname, or username.
+// SERVER host IDs only matter if this file is for the license server itself, which is the case when it has counted features.
+func checkLicenseHostIDs(lic *licenseFile) {
+	macAddresses := localMACAddresses()
+	hostname, _ := os.Hostname()
+	username := localUsername()
+
+	mismatched := make(map[string][]string)
+	for _, feature := range lic.Features {
+		if !strings.EqualFold(feature.Count, "uncounted") {
+			continue
+		}
+		if hostID, exists := feature.Options["HOSTID"]; exists && !hostIDMatches(hostID, macAddresses, hostname, username) {
+			mismatched[hostID] = appendUnique(mismatched[hostID], feature.Name)
+		}
+
+		// Individual licenses are also tied to the person using them.
+		if licensedUser, exists := feature.Options["USER"]; exists && username != "" && !strings.EqualFold(licensedUser, username) {
+			lic.Warnings = append(lic.Warnings, fmt.Sprintf("%s is licensed to the user \"%s\", but you're logged in as \"%s\".", feature.Name, licensedUser, username))
+		}
+	}
+	hostIDs := make([]string, 0, len(mismatched))
+	for hostID := range mismatched {
+		hostIDs = append(hostIDs, hostID)
+	}
+	sort.Strings(hostIDs)
+	for _, hostID := range hostIDs {
+		lic.Warnings = append(lic.Warnings, fmt.Sprintf("These features are locked to the host ID \"%s\", which doesn't match this machine: %s", hostID, strings.Join(mismatched[hostID], ", ")))
+	}
+
+	countedFeatures := false
+	for _, feature := range lic.Features {
+		if !strings.EqualFold(feature.Count, "uncounted") {
+			countedFeatures = true
+			break
+		}
+	}
+	if countedFeatures {
+		for _, server := range lic.Servers {
+			if !hostIDMatches(server.HostID, macAddresses, hostname, username) {
+				lic.Warnings = append(lic.Warnings, fmt.Sprintf("This looks like a license server's license file, but the server's host ID \"%s\" doesn't match this machine. "+
+					"Client machines should use a license file with only SERVER and USE_SERVER lines.", server.HostID))
+			}
+		}
+	}
+}
+
+// Check a FlexNet host ID against this machine. Host IDs we can't check, such as license numbers, are assumed to match.
+func hostIDMatches(hostID string, macAddresses []string, hostname string, username string) bool {
+	hostID = strings.Trim(hostID, "\"")
+
+	// Some licenses list several host IDs. Any of them will do.
+	if ids := strings.Fields(hostID); len(ids) > 1 {
+		for _, id := range ids {
+			if hostIDMatches(id, macAddresses, hostname, username) {
+				return true
+			}
+		}
+		return false
+	}
+
+	upperHostID := strings.ToUpper(hostID)
+	switch {
+	case upperHostID == "ANY" || upperHostID == "DEMO":
+		return true
+	case strings.HasPrefix(upperHostID, "HOSTNAME="):
+		return strings.EqualFold(hostID[len("HOSTNAME="):], hostname)
+	case strings.HasPrefix(upperHostID, "USER="):
+		return strings.EqualFold(hostID[len("USER="):], username)
+	case strings.Contains(hostID, "="):
+		return true // ID=, INTERNET=, and friends aren't something we can check.
+	}
+
+	normalizedHostID := normalizeMACAddress(hostID)
+	if len(normalizedHostID) != 12 {
+		return true
+	}
+	for _, macAddress := range macAddresses {
+		if macAddress == normalizedHostID {
+			return true
+		}
+	}
+	return false
+}
+
+// The MAC addresses of this machine, formatted the way FlexNet host IDs are: 12 lowercase hex digits without separators.
+func localMACAddresses() []string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	var macAddresses []string
+	for _, networkInterface := range interfaces {
+		macAddress := normalizeMACAddress(networkInterface.HardwareAddr.String())
+		if len(macAddress) == 12 && macAddress != "000000000000" {
+			macAddresses = append(macAddresses, macAddress)
+		}
+	}
+	return macAddresses
+}
+
+func normalizeMACAddress(macAddress string) string {
+	macAddress = strings.ToLower(macAddress)
+	macAddress = strings.NewReplacer(":", "", "-", "", ".", "").Replace(macAddress)
+	for _, character := range macAddress {
+		if !strings.ContainsRune("0123456789abcdef", character) {
+			return ""
+		}
+	}
+	return macAddress
+}
+
+// The current username, without the domain Windows puts in front of it.
+func localUsername() string {
+	currentUser, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	username := currentUser.Username
+	if index := strings.LastIndex(username, "\\"); index >= 0 {
+		username = username[index+1:]
+	}
+	return username
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func sortedDates(dates map[time.Time][]string) []time.Time {
+	sorted := make([]time.Time, 0, len(dates))
+	for date := range dates {
+		sorted = append(sorted, date)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	return sorted
+}
+
+// Compare the products being installed with what a license covers. uncovered are selected products the license can't check out.
+// unselected are products the license covers that weren't selected. Expired features don't count.
+func checkLicenseCoverage(lic licenseFile, products []string, availableProducts []string) (uncovered []string, unselected []string) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	licensedFeatures := make(map[string]struct{})
+	for _, feature := range lic.Features {
+		if feature.Permanent || !feature.Expiry.Before(today) {
+			licensedFeatures[strings.ToLower(feature.Name)] = struct{}{}
+		}
+	}
+
+	selected := make(map[string]struct{}, len(products))
+	for _, product := range products {
+		selected[product] = struct{}{}
+		if _, free := unlicensedProducts[product]; free {
+			continue
+		}
+		if _, licensed := licensedFeatures[strings.ToLower(licenseFeatureFor(product))]; !licensed {
+			uncovered = append(uncovered, product)
+		}
+	}
+
+	for _, product := range availableProducts {
+		if _, alreadySelected := selected[product]; alreadySelected {
+			continue
+		}
+		if _, free := unlicensedProducts[product]; free {
+			continue
+		}
+		if _, licensed := licensedFeatures[strings.ToLower(licenseFeatureFor(product))]; licensed {
+			unselected = appendUnique(unselected, product)
+		}
+	}
+	sort.Strings(unselected)
+	return uncovered, unselected
+}
+
+// Warn about products the license doesn't cover (and licensed products that weren't picked) and offer to fix the selection.
+// Also returns a warning for each selected product that's still not covered.
+func adjustProductsForLicense(rl *readline.Instance, lic licenseFile, products []string, availableProducts []string) ([]string, []string) {
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	// Network licenses for clients don't list features, so there's nothing to compare against.
+	if len(lic.Features) == 0 {
+		return products, nil
+	}
+
+	uncovered, unselected := checkLicenseCoverage(lic, products, availableProducts)
+
+	// We can't see what the license server has, so the products your local license files don't cover aren't necessarily unlicensed.
+	if len(uncovered) > 0 && lic.ServerBacked {
+		fmt.Println("The following products aren't in your local license files, so they'll need to be checked out from your license server:")
+		for _, product := range uncovered {
+			fmt.Println("- " + product + " (license feature " + licenseFeatureFor(product) + ")")
+		}
+	} else if len(uncovered) > 0 {
+		fmt.Println(yellowText("Your license file does not cover the following products, so you won't be able to use them:"))
+		for _, product := range uncovered {
+			fmt.Println(yellowText("- " + product + " (license feature " + licenseFeatureFor(product) + ")"))
+		}
+		if len(uncovered) < len(products) && promptYesNo(rl, "Would you like to remove these products from your selection?") {
+			var covered []string
+			for _, product := range products {
+				if len(checkProductsExist([]string{product}, uncovered)) > 0 {
+					covered = append(covered, product)
+				}
+			}
+			products = covered
+			uncovered = nil
+		}
+	}
+
+	var warnings []string
+	for _, product := range uncovered {
+		if lic.ServerBacked {
+			warnings = append(warnings, product+" isn't in your local license files, so it needs to come from your license server.")
+		} else {
+			warnings = append(warnings, product+" isn't covered by your license (license feature "+licenseFeatureFor(product)+").")
+		}
+	}
+
+	if len(unselected) > 0 {
+		fmt.Println("Your license file also covers the following products, which you did not select:")
+		for _, product := range unselected {
+			fmt.Println("- " + product)
+		}
+		if promptYesNo(rl, "Would you like to add these products to your selection?") {
+			products = append(products, unselected...)
+		}
+	}
+
+	return products, warnings
+}
+
+// Turn what was entered at the license prompt into a list of license files. Several files can be given, separated by commas
+// or semicolons, and a folder means every license file in it.
+func expandLicensePaths(input string) ([]string, error) {
+	entries := []string{input}
+	if _, err := os.Stat(input); err != nil {
+		entries = strings.FieldsFunc(input, func(character rune) bool {
+			return character == ',' || character == ';'
+		})
+	}
+
+	var licensePaths []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		info, err := os.Stat(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !isLicenseFileName(entry) {
+				return nil, fmt.Errorf("\"%s\" does not have a .dat, .lic, or .xml file extension", entry)
+			}
+			licensePaths = appendUnique(licensePaths, entry)
+			continue
+		}
+
+		dirEntries, err := os.ReadDir(entry)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, dirEntry := range dirEntries {
+			if dirEntry.Type().IsRegular() && isLicenseFileName(dirEntry.Name()) {
+				licensePaths = appendUnique(licensePaths, filepath.Join(entry, dirEntry.Name()))
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("\"%s\" does not contain any .dat, .lic, or .xml files", entry)
+		}
+	}
+	if len(licensePaths) == 0 {
+		return nil, fmt.Errorf("no license files were given")
+	}
+	return licensePaths, nil
+}
+
+func isLicenseFileName(name string) bool {
+	switch filepath.Ext(name) {
+	case ".dat", ".lic", ".xml":
+		return true
+	}
+	return false
+}
+
+// Check each license file the same way, and then check them against each other. Returns false if any of them shouldn't be used.
+// Anything that depends on this machine, such as host IDs and reaching license servers, is skipped unless thisMachine is set.
+func checkLicenseFiles(rl *readline.Instance, licensePaths []string, thisMachine bool) ([]licenseFile, bool) {
+	redText := color.New(color.FgRed).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	var lics []licenseFile
+	for _, licensePath := range licensePaths {
+
+		// Catch broken, expired, or misplaced licenses now instead of after the installation.
+		lic, err := validateLicenseFile(licensePath, thisMachine)
+		if err != nil {
+			fmt.Println(redText("Error reading license file \""+licensePath+"\": ", err))
+			return nil, false
+		}
+		if !printLicenseProblems(lic) {
+			fmt.Println(redText("Please fix the license file or provide a different one."))
+			return nil, false
+		}
+		if len(lic.Warnings) > 0 && !promptYesNo(rl, "Would you like to use this license file anyway?") {
+			return nil, false
+		}
+		if thisMachine && lic.isNetworkLicense() {
+			unreachable := unreachableLicenseServers(lic)
+			if len(unreachable) > 0 && !promptYesNo(rl, "Would you like to use this license file anyway?") {
+				return nil, false
+			}
+			lic.Warnings = append(lic.Warnings, unreachable...)
+		}
+		lics = append(lics, lic)
+	}
+
+	if conflicts := checkLicenseConflicts(lics); len(conflicts) > 0 {
+		fmt.Println(yellowText("Your license files overlap:"))
+		for _, conflict := range conflicts {
+			fmt.Println(yellowText("- " + conflict))
+		}
+		if !promptYesNo(rl, "Would you like to use these license files anyway?") {
+			return nil, false
+		}
+	}
+	return lics, true
+}
+
+// Look for the same INCREMENT in more than one license file. Identical ones are just clutter, but ones with different terms
+// mean you might not get the license you expect, since FlexNet uses whichever it comes across first.
+func checkLicenseConflicts(lics []licenseFile) []string {
+	type seenFeature struct {
+		lic     licenseFile
+		feature licenseFeature
+	}
+	seen := make(map[string][]seenFeature)
+	var keys []string
+	for _, lic := range lics {
+		for _, feature := range lic.Features {
+			key := strings.ToUpper(feature.Name) + " " + feature.Vendor + " " + feature.Version
+			if _, exists := seen[key]; !exists {
+				keys = append(keys, key)
+			}
+			seen[key] = append(seen[key], seenFeature{lic: lic, feature: feature})
+		}
+	}
+
+	var conflicts []string
+	for _, key := range keys {
+		for i, first := range seen[key] {
+			for _, second := range seen[key][i+1:] {
+				if first.lic.Path == second.lic.Path {
+					continue // Stacking several INCREMENTs for a feature in one file is normal.
+				}
+				where := fmt.Sprintf("\"%s\" (line %d) and \"%s\" (line %d)", first.lic.Path, first.feature.Line, second.lic.Path, second.feature.Line)
+				if normalizeLicenseLine(first.feature.Raw) == normalizeLicenseLine(second.feature.Raw) {
+					conflicts = append(conflicts, fmt.Sprintf("%s %s appears in both %s.", first.feature.Name, first.feature.Version, where))
+				} else {
+					conflicts = append(conflicts, fmt.Sprintf("%s %s has different terms in %s.", first.feature.Name, first.feature.Version, where))
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// A license line without its continuations and extra whitespace, so the same line copied from different places still compares equal.
+func normalizeLicenseLine(line string) string {
+	var fields []string
+	for _, field := range strings.Fields(line) {
+		if field != "\\" {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// Combine several license files into one for checking what they cover together. Warnings are kept, along with which file they came from.
+func mergeLicenses(lics []licenseFile) licenseFile {
+	var merged licenseFile
+	var paths []string
+	for _, lic := range lics {
+		paths = append(paths, lic.Path)
+		merged.Servers = append(merged.Servers, lic.Servers...)
+		merged.Daemons = append(merged.Daemons, lic.Daemons...)
+		merged.Features = append(merged.Features, lic.Features...)
+		merged.UseServer = merged.UseServer || lic.UseServer
+		merged.ServerBacked = merged.ServerBacked || lic.isClientNetworkLicense()
+		for _, warning := range lic.Warnings {
+			merged.Warnings = append(merged.Warnings, filepath.Base(lic.Path)+": "+warning)
+		}
+	}
+	merged.Warnings = append(merged.Warnings, checkLicenseConflicts(lics)...)
+	merged.Path = strings.Join(paths, ", ")
+	return merged
+}
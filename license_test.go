@@ -0,0 +1,184 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testLicense = `# A comment that's ignored.
+SERVER licenseserver 0123456789ab 27000
+DAEMON MLM port=27001
+INCREMENT MATLAB MLM 47 31-dec-2030 10 \
+	VENDOR_STRING="QQ a b c" \
+	SIGN="0000 1111"
+INCREMENT SIMULINK MLM 47 permanent uncounted HOSTID=ANY
+`
+
+func containsText(values []string, text string) bool {
+	for _, value := range values {
+		if strings.Contains(value, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseLicense(t *testing.T) {
+	lic := parseLicense(testLicense, "license.lic")
+	if len(lic.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", lic.Errors)
+	}
+	if len(lic.Servers) != 1 || lic.Servers[0].Host != "licenseserver" || lic.Servers[0].Port != 27000 {
+		t.Errorf("servers = %+v", lic.Servers)
+	}
+	if len(lic.Daemons) != 1 || lic.Daemons[0].Name != "MLM" || lic.Daemons[0].Port != 27001 {
+		t.Errorf("daemons = %+v", lic.Daemons)
+	}
+	if len(lic.Features) != 2 {
+		t.Fatalf("got %d features, want 2", len(lic.Features))
+	}
+
+	// The continued lines belong to the first INCREMENT, and quoted values stay in one piece.
+	matlab := lic.Features[0]
+	if matlab.Name != "MATLAB" || matlab.Line != 4 || matlab.Options["VENDOR_STRING"] != "QQ a b c" || matlab.Options["SIGN"] != "0000 1111" {
+		t.Errorf("MATLAB feature = %+v", matlab)
+	}
+	if !matlab.Expiry.Equal(time.Date(2030, time.December, 31, 0, 0, 0, 0, time.UTC)) || matlab.Permanent {
+		t.Errorf("MATLAB expiry = %v, permanent = %v", matlab.Expiry, matlab.Permanent)
+	}
+	if simulink := lic.Features[1]; !simulink.Permanent || simulink.Count != "uncounted" || simulink.Line != 7 {
+		t.Errorf("SIMULINK feature = %+v", simulink)
+	}
+}
+
+func TestParseLicenseLineEndings(t *testing.T) {
+	lic := parseLicense(strings.ReplaceAll(testLicense, "\n", "\r\n"), "license.lic")
+	if len(lic.Features) != 2 {
+		t.Errorf("got %d features from a CRLF file, want 2", len(lic.Features))
+	}
+	if runtime.GOOS != "windows" && !containsText(lic.Errors, "Windows line endings") {
+		t.Errorf("expected a Windows line endings error, got %v", lic.Errors)
+	}
+
+	lic = parseLicense(strings.Replace(testLicense, "\n", "\r", 1), "license.lic")
+	if !containsText(lic.Errors, "stray carriage returns") {
+		t.Errorf("expected a stray carriage return error, got %v", lic.Errors)
+	}
+}
+
+func TestParseLicenseEmailDamage(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{"smart quotes", strings.Replace(testLicense, `"QQ a b c"`, "“QQ a b c”", 1), "doesn't belong in a license file"},
+		{"html", strings.Replace(testLicense, "SIGN=", "<br>SIGN=", 1), "HTML or email encoding"},
+		{"quoted-printable", strings.Replace(testLicense, "port=27001", "port=3D27001", 1), "HTML or email encoding"},
+		{"wrapped line", strings.Replace(testLicense, "10 \\\n", "10\n", 1), "isn't a license file keyword"},
+		{"cut off", strings.TrimSuffix(testLicense, "HOSTID=ANY\n") + "\\\n", "may have been cut off"},
+		{"empty", "# Nothing here.\n", "doesn't contain any licenses"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lic := parseLicense(test.contents, "license.lic")
+			if !containsText(lic.Errors, test.want) {
+				t.Errorf("expected an error containing %q, got %v", test.want, lic.Errors)
+			}
+		})
+	}
+
+	lic := parseLicense(strings.Replace(testLicense, "10 \\\n", "10 \\  \n", 1), "license.lic")
+	if !containsText(lic.Warnings, "spaces after its line continuation") {
+		t.Errorf("expected a trailing space warning, got %v", lic.Warnings)
+	}
+}
+
+func TestParseLicenseDate(t *testing.T) {
+	tests := []struct {
+		date      string
+		want      time.Time
+		permanent bool
+		fails     bool
+	}{
+		{date: "31-dec-2030", want: time.Date(2030, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{date: "1-JAN-2026", want: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{date: "permanent", permanent: true},
+		{date: "1-jan-0", permanent: true},
+		{date: "31-feb-2030", fails: true},
+		{date: "2030-12-31", fails: true},
+		{date: "31--2030", fails: true},
+	}
+	for _, test := range tests {
+		expiry, permanent, err := parseLicenseDate(test.date)
+		if (err != nil) != test.fails {
+			t.Errorf("parseLicenseDate(%q) error = %v", test.date, err)
+			continue
+		}
+		if !test.fails && (!expiry.Equal(test.want) || permanent != test.permanent) {
+			t.Errorf("parseLicenseDate(%q) = %v, %v", test.date, expiry, permanent)
+		}
+	}
+}
+
+func TestCheckLicenseExpiry(t *testing.T) {
+	lic := parseLicense(testLicense, "license.lic")
+	checkLicenseExpiry(&lic, time.Date(2030, time.December, 20, 12, 0, 0, 0, time.UTC))
+	if !containsText(lic.Warnings, "Expires on 31-Dec-2030 (in 11 days): MATLAB") {
+		t.Errorf("expected MATLAB to be expiring soon, got %v", lic.Warnings)
+	}
+
+	lic = parseLicense(strings.Replace(testLicense, "permanent", "1-jan-2020", 1), "license.lic")
+	checkLicenseExpiry(&lic, time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if !containsText(lic.Errors, "Every feature in this license file has expired") {
+		t.Errorf("expected every feature to have expired, got %v", lic.Errors)
+	}
+}
+
+func TestMergeLicensesWithNetworkLicense(t *testing.T) {
+	network := parseLicense("SERVER licenseserver ANY 27000\nUSE_SERVER\n", "network.lic")
+	toolbox := parseLicense("INCREMENT Signal_Toolbox MLM 47 permanent uncounted HOSTID=ANY\n", "signal.lic")
+	if !network.isClientNetworkLicense() || toolbox.isClientNetworkLicense() {
+		t.Fatal("only network.lic should be a client network license")
+	}
+
+	merged := mergeLicenses([]licenseFile{network, toolbox})
+	if !merged.ServerBacked {
+		t.Error("a merge including a client network license should be server-backed")
+	}
+	if mergeLicenses([]licenseFile{toolbox}).ServerBacked {
+		t.Error("a merge without a client network license shouldn't be server-backed")
+	}
+	uncovered, _ := checkLicenseCoverage(merged, []string{"MATLAB", "Signal_Processing_Toolbox"}, nil)
+	if len(uncovered) != 1 || uncovered[0] != "MATLAB" {
+		t.Errorf("uncovered = %v, want only MATLAB", uncovered)
+	}
+}
+
+func TestValidateLicenseFileForAnotherMachine(t *testing.T) {
+	licensePath := filepath.Join(t.TempDir(), "license.lic")
+	contents := "INCREMENT MATLAB MLM 47 permanent uncounted HOSTID=HOSTNAME=some-other-machine-entirely\n"
+	if err := os.WriteFile(licensePath, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lic, err := validateLicenseFile(licensePath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsText(lic.Warnings, "doesn't match this machine") {
+		t.Errorf("expected a host ID warning, got %v", lic.Warnings)
+	}
+
+	lic, err = validateLicenseFile(licensePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if containsText(lic.Warnings, "doesn't match this machine") {
+		t.Errorf("host IDs were checked for a license meant for another machine: %v", lic.Warnings)
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	readline "github.com/Jestzer/readlineJestzer"
+	"github.com/fatih/color"
+)
+
+// Where a license file goes. Shared licenses go in the installation's licenses folder, where everyone using it will find them.
+// Otherwise, they go in each listed user's own license folder for the release.
+type licensePlacement struct {
+	Shared bool
+	Users  []string
+}
+
+// A license file that's been checked and is waiting to be placed. Name is what it'll be called once placed.
+type licenseSource struct {
+	Path string
+	Name string
+	Hash string
+}
+
+// Get license files ready to be placed. Files from different folders can share a name, so later ones get a number added to keep them apart.
+// Each file is also hashed so we can tell if it changes or can't be read by the time it's placed.
+func prepareLicenseSources(licensePaths []string) ([]licenseSource, error) {
+	var sources []licenseSource
+	usedNames := make(map[string]bool)
+	for _, licensePath := range licensePaths {
+		hash, err := hashFile(licensePath)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(licensePath)
+		extension := filepath.Ext(name)
+		for number := 2; usedNames[strings.ToLower(name)]; number++ {
+			name = strings.TrimSuffix(filepath.Base(licensePath), extension) + "_" + strconv.Itoa(number) + extension
+		}
+		usedNames[strings.ToLower(name)] = true
+		sources = append(sources, licenseSource{Path: licensePath, Name: name, Hash: hash})
+	}
+	return sources, nil
+}
+
+// The folder MATLAB checks for a user's own licenses, relative to their home folder.
+func userLicenseDirectory(homeDir string, platform string, release string) string {
+	switch platform {
+	case "windows":
+		return filepath.Join(homeDir, "AppData", "Roaming", "MathWorks", "MATLAB", release+"_licenses")
+	case "macOSx64", "macOSARM":
+		return filepath.Join(homeDir, "Library", "Application Support", "MathWorks", "MATLAB", release+"_licenses")
+	}
+	return filepath.Join(homeDir, ".matlab", release+"_licenses")
+}
+
+// Individual licenses are tied to a username. These are the usernames a license file is tied to, if any.
+func individualLicenseUsers(lic licenseFile) []string {
+	var users []string
+	for _, feature := range lic.Features {
+		if licensedUser, exists := feature.Options["USER"]; exists {
+			users = appendUnique(users, licensedUser)
+		}
+		hostID := strings.Trim(feature.Options["HOSTID"], "\"")
+		if strings.HasPrefix(strings.ToUpper(hostID), "USER=") {
+			users = appendUnique(users, hostID[len("USER="):])
+		}
+	}
+	return users
+}
+
+// Ask whether the license should be shared by everyone using the installation or only belong to certain users.
+func promptLicensePlacement(rl *readline.Instance, lic licenseFile) licensePlacement {
+	redText := color.New(color.FgRed).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	// Individual licenses only work for the person they're licensed to, so putting them where everyone will pick them up causes trouble.
+	licensedUsers := individualLicenseUsers(lic)
+	defaultAnswer := "installation"
+	if len(licensedUsers) > 0 {
+		defaultAnswer = "user"
+		fmt.Println(yellowText("This license is tied to the username(s) " + strings.Join(licensedUsers, ", ") + ". " +
+			"It should be placed in each user's own license folder, not shared with the whole installation."))
+	}
+
+	for {
+		answer := promptUser(rl, "Where would you like to place your license? Enter \"installation\" to share it with everyone using this installation, "+
+			"\"user\" to place it in your own license folder, or a list of usernames separated by commas to place it in each of their license folders. "+
+			"Press Enter to use \""+defaultAnswer+"\"")
+		if answer == "" {
+			answer = defaultAnswer
+		}
+
+		switch strings.ToLower(answer) {
+		case "installation":
+			if len(licensedUsers) > 0 && !promptYesNo(rl, "Are you sure you'd like to share a license tied to a username?") {
+				continue
+			}
+			return licensePlacement{Shared: true}
+		case "user":
+			username := localUsername()
+			if username == "" {
+				fmt.Println(redText("Could not figure out who you're logged in as. Please enter your username instead."))
+				continue
+			}
+			return licensePlacement{Users: []string{username}}
+		}
+
+		var users []string
+		for _, username := range strings.FieldsFunc(answer, func(character rune) bool {
+			return character == ',' || character == ' '
+		}) {
+			users = appendUnique(users, username)
+		}
+		if len(users) == 0 {
+			continue
+		}
+
+		// Only an administrator can write to other people's home folders.
+		placement := licensePlacement{Users: users}
+		if runtime.GOOS != "windows" && os.Geteuid() != 0 && (len(users) > 1 || users[0] != localUsername()) {
+			fmt.Println(redText("You need to run this program as root to place licenses for other users."))
+			continue
+		}
+		validUsers := true
+		for _, username := range users {
+			if _, err := user.Lookup(username); err != nil {
+				fmt.Println(redText("The user \"" + username + "\" could not be found."))
+				validUsers = false
+			}
+		}
+		if validUsers {
+			return placement
+		}
+	}
+}
+
+// Create a user's license folder (and any folders above it that don't exist yet) and hand them over to that user.
+func createUserLicenseDirectory(username string, platform string, release string) (string, error) {
+	account, err := user.Lookup(username)
+	if err != nil {
+		return "", err
+	}
+	licensesDirectory := userLicenseDirectory(account.HomeDir, platform, release)
+
+	var created []string
+	for path := licensesDirectory; path != account.HomeDir && path != filepath.Dir(path); path = filepath.Dir(path) {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		created = append(created, path)
+	}
+	if err := os.MkdirAll(licensesDirectory, 0755); err != nil {
+		return "", err
+	}
+
+	// Windows doesn't do ownership this way, and new folders in your profile are already yours.
+	if runtime.GOOS != "windows" {
+		for _, path := range created {
+			if err := chownToUser(path, account); err != nil {
+				return "", err
+			}
+		}
+	}
+	return licensesDirectory, nil
+}
+
+// Where a placement should leave each license file for an installation, worked out before anything is copied so a copy that
+// fails still gets checked. Per-user licenses only count for MATLAB, since that's what reads them.
+func expectedLicensePaths(licenses []licenseSource, placement licensePlacement, plan installPlan, platform string, release string) []string {
+	var directories []string
+	if placement.Shared {
+		directories = append(directories, filepath.Join(plan.Destination, "licenses"))
+	} else if !plan.Polyspace {
+		for _, username := range placement.Users {
+			account, err := user.Lookup(username)
+			if err != nil {
+				continue // Placing their license will fail and say why.
+			}
+			directories = append(directories, userLicenseDirectory(account.HomeDir, platform, release))
+		}
+	}
+
+	var paths []string
+	for _, directory := range directories {
+		for _, license := range licenses {
+			paths = append(paths, filepath.Join(directory, license.Name))
+		}
+	}
+	return paths
+}
+
+// Give a file or folder to a user and their primary group.
+func chownToUser(path string, account *user.User) error {
+	uid, err := strconv.Atoi(account.Uid)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(account.Gid)
+	if err != nil {
+		return err
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// Place license files in the license folder of each user. Each user's copies are only readable by them.
+// Returns where they were placed.
+func placeUserLicenses(licenses []licenseSource, users []string, platform string, release string) ([]string, error) {
+	var placed []string
+	for _, username := range users {
+		licensesDirectory, err := createUserLicenseDirectory(username, platform, release)
+		if err != nil {
+			return placed, fmt.Errorf("could not create %s's license folder: %w", username, err)
+		}
+		for _, license := range licenses {
+			destPath, backupPath, err := installLicenseFile(license, licensesDirectory, 0600)
+			if err != nil {
+				return placed, fmt.Errorf("could not place %s's license file: %w", username, err)
+			}
+			if runtime.GOOS != "windows" {
+				account, err := user.Lookup(username)
+				if err != nil {
+					return placed, err
+				}
+				if err := chownToUser(destPath, account); err != nil {
+					return placed, fmt.Errorf("could not give %s their license file: %w", username, err)
+				}
+			}
+			reportLicensePlacement(destPath, backupPath)
+			placed = append(placed, destPath)
+		}
+	}
+	return placed, nil
+}
+
+// Copy a license file into a licenses folder all at once, or not at all. The copy is written next to its final location,
+// checked against the hash of the license file that was validated, and only then moved into place.
+// A different license file already using the same name is kept with a .bak extension rather than overwritten.
+// Returns where the license file and the backup (if one was needed) ended up.
+func installLicenseFile(license licenseSource, licensesDirectory string, mode os.FileMode) (string, string, error) {
+	licensePath, licenseHash := license.Path, license.Hash
+	if err := os.MkdirAll(licensesDirectory, 0755); err != nil {
+		return "", "", fmt.Errorf("could not create \"%s\": %w", licensesDirectory, err)
+	}
+	destPath := filepath.Join(licensesDirectory, license.Name)
+
+	sourceHash, err := hashFile(licensePath)
+	if err != nil {
+		return "", "", err
+	}
+	if sourceHash != licenseHash {
+		return "", "", fmt.Errorf("\"%s\" changed after it was checked", licensePath)
+	}
+
+	// Nothing to do if this exact license file is already there, which happens when adding products to an existing installation.
+	if existingHash, err := hashFile(destPath); err == nil && existingHash == licenseHash {
+		return destPath, "", os.Chmod(destPath, mode)
+	}
+
+	source, err := os.Open(licensePath)
+	if err != nil {
+		return "", "", err
+	}
+	defer source.Close()
+
+	temp, err := os.CreateTemp(licensesDirectory, ".mpm-license-*")
+	if err != nil {
+		return "", "", err
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath) // Does nothing once the file has been moved into place.
+
+	_, err = io.Copy(temp, source)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("could not copy the license file: %w", err)
+	}
+	copyHash, err := hashFile(tempPath)
+	if err != nil {
+		return "", "", err
+	}
+	if copyHash != licenseHash {
+		return "", "", fmt.Errorf("the copied license file doesn't match the original")
+	}
+	if err := os.Chmod(tempPath, mode); err != nil {
+		return "", "", err
+	}
+
+	backupPath := ""
+	if _, err := os.Lstat(destPath); err == nil {
+		backupPath = destPath + "." + time.Now().Format("20060102-150405") + ".bak"
+		if err := os.Rename(destPath, backupPath); err != nil {
+			return "", "", fmt.Errorf("could not move the existing license file out of the way: %w", err)
+		}
+	}
+	if err := os.Rename(tempPath, destPath); err != nil {
+		if backupPath != "" {
+			os.Rename(backupPath, destPath)
+		}
+		return "", "", err
+	}
+	return destPath, backupPath, nil
+}
+
+// Say where a license file ended up, and where the one it replaced went.
+func reportLicensePlacement(destPath string, backupPath string) {
+	greenText := color.New(color.FgHiGreen).SprintFunc()
+	message := "Your license file has been placed in \"" + destPath + "\"."
+	if backupPath != "" {
+		message += " A different license file was already there, so it was saved as \"" + backupPath + "\"."
+	}
+	fmt.Println(greenText(message))
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// A system library MATLAB needs on Linux, along with the package that provides it on each family of distros.
+// Libraries are found by their soname. Anything without one (such as fonts) is found with Globs instead.
+type linuxLibrary struct {
+	Soname     string
+	Globs      []string
+	Since      string // The first release that needs it. Empty means every release.
+	Debian     string
+	RedHat     string
+	SUSE       string
+	RenamedT64 bool // Ubuntu 24.04 and Debian 13 added "t64" to the end of this package's name.
+}
+
+// What MATLAB needs outside of its own installation. This follows the packages MathWorks installs in its own container images.
+var linuxLibraries = []linuxLibrary{
+	{Soname: "libX11.so.6", Debian: "libx11-6", RedHat: "libX11", SUSE: "libX11-6"},
+	{Soname: "libXext.so.6", Debian: "libxext6", RedHat: "libXext", SUSE: "libXext6"},
+	{Soname: "libXt.so.6", Debian: "libxt6", RedHat: "libXt", SUSE: "libXt6"},
+	{Soname: "libXtst.so.6", Debian: "libxtst6", RedHat: "libXtst", SUSE: "libXtst6"},
+	{Soname: "libXrandr.so.2", Debian: "libxrandr2", RedHat: "libXrandr", SUSE: "libXrandr2"},
+	{Soname: "libXrender.so.1", Debian: "libxrender1", RedHat: "libXrender", SUSE: "libXrender1"},
+	{Soname: "libXi.so.6", Debian: "libxi6", RedHat: "libXi", SUSE: "libXi6"},
+	{Soname: "libXcomposite.so.1", Debian: "libxcomposite1", RedHat: "libXcomposite", SUSE: "libXcomposite1"},
+	{Soname: "libXcursor.so.1", Debian: "libxcursor1", RedHat: "libXcursor", SUSE: "libXcursor1"},
+	{Soname: "libXdamage.so.1", Debian: "libxdamage1", RedHat: "libXdamage", SUSE: "libXdamage1"},
+	{Soname: "libXfixes.so.3", Debian: "libxfixes3", RedHat: "libXfixes", SUSE: "libXfixes3"},
+	{Soname: "libXft.so.2", Debian: "libxft2", RedHat: "libXft", SUSE: "libXft2"},
+	{Soname: "libXinerama.so.1", Debian: "libxinerama1", RedHat: "libXinerama", SUSE: "libXinerama1"},
+	{Soname: "libXxf86vm.so.1", Debian: "libxxf86vm1", RedHat: "libXxf86vm", SUSE: "libXxf86vm1"},
+	{Soname: "libGL.so.1", Debian: "libgl1", RedHat: "mesa-libGL", SUSE: "libGL1"},
+	{Soname: "libnss3.so", Debian: "libnss3", RedHat: "nss", SUSE: "mozilla-nss"},
+	{Soname: "libnspr4.so", Debian: "libnspr4", RedHat: "nspr", SUSE: "mozilla-nspr"},
+	{Soname: "libasound.so.2", Debian: "libasound2", RedHat: "alsa-lib", SUSE: "libasound2", RenamedT64: true},
+	{Soname: "libcups.so.2", Debian: "libcups2", RedHat: "cups-libs", SUSE: "libcups2", RenamedT64: true},
+	{Soname: "libglib-2.0.so.0", Debian: "libglib2.0-0", RedHat: "glib2", SUSE: "libglib-2_0-0", RenamedT64: true},
+	{Soname: "libgtk-3.so.0", Debian: "libgtk-3-0", RedHat: "gtk3", SUSE: "libgtk-3-0", RenamedT64: true},
+	{Soname: "libcairo.so.2", Debian: "libcairo2", RedHat: "cairo", SUSE: "libcairo2"},
+	{Soname: "libcairo-gobject.so.2", Debian: "libcairo-gobject2", RedHat: "cairo-gobject", SUSE: "libcairo-gobject2"},
+	{Soname: "libpango-1.0.so.0", Debian: "libpango-1.0-0", RedHat: "pango", SUSE: "libpango-1_0-0"},
+	{Soname: "libpangocairo-1.0.so.0", Debian: "libpangocairo-1.0-0", RedHat: "pango", SUSE: "libpangocairo-1_0-0"},
+	{Soname: "libpangoft2-1.0.so.0", Debian: "libpangoft2-1.0-0", RedHat: "pango", SUSE: "libpangoft2-1_0-0"},
+	{Soname: "libfontconfig.so.1", Debian: "libfontconfig1", RedHat: "fontconfig", SUSE: "libfontconfig1"},
+	{Soname: "libfreetype.so.6", Debian: "libfreetype6", RedHat: "freetype", SUSE: "libfreetype6"},
+	{Soname: "libICE.so.6", Debian: "libice6", RedHat: "libICE", SUSE: "libICE6"},
+	{Soname: "libuuid.so.1", Debian: "libuuid1", RedHat: "libuuid", SUSE: "libuuid1"},
+	{Soname: "libudev.so.1", Debian: "libudev1", RedHat: "systemd-libs", SUSE: "libudev1"},
+	{Soname: "libz.so.1", Debian: "zlib1g", RedHat: "zlib", SUSE: "libz1"},
+	{Soname: "libsndfile.so.1", Debian: "libsndfile1", RedHat: "libsndfile", SUSE: "libsndfile1"},
+	{Soname: "libcap.so.2", Debian: "libcap2", RedHat: "libcap", SUSE: "libcap2"},
+	{Soname: "libpam.so.0", Debian: "libpam0g", RedHat: "pam", SUSE: "pam"},
+	{Soname: "libltdl.so.7", Debian: "libltdl7", RedHat: "libtool-ltdl", SUSE: "libltdl7"},
+	{Soname: "libgstreamer-1.0.so.0", Debian: "libgstreamer1.0-0", RedHat: "gstreamer1", SUSE: "libgstreamer-1_0-0"},
+	{Soname: "libgstapp-1.0.so.0", Debian: "libgstreamer-plugins-base1.0-0", RedHat: "gstreamer1-plugins-base", SUSE: "libgstapp-1_0-0"},
+	{Soname: "libgbm.so.1", Since: "R2020b", Debian: "libgbm1", RedHat: "mesa-libgbm", SUSE: "libgbm1"},
+	{Soname: "libdrm.so.2", Since: "R2020b", Debian: "libdrm2", RedHat: "libdrm", SUSE: "libdrm2"},
+	{Soname: "libwayland-client.so.0", Since: "R2022b", Debian: "libwayland-client0", RedHat: "libwayland-client", SUSE: "libwayland-client0"},
+	{Globs: []string{"/usr/share/fonts/*/*", "/usr/share/fonts/*/*/*", "/usr/share/X11/fonts/*/*"}, Debian: "fonts-dejavu-core", RedHat: "dejavu-sans-fonts", SUSE: "dejavu-fonts"},
+}
+
+// Where libraries usually live, for when ldconfig isn't around to ask.
+var linuxLibraryDirectories = []string{
+	"/lib", "/lib64", "/usr/lib", "/usr/lib64", "/usr/local/lib",
+	"/lib/x86_64-linux-gnu", "/usr/lib/x86_64-linux-gnu",
+}
+
+// The libraries a release needs.
+func linuxLibrariesFor(release string) []linuxLibrary {
+	var libraries []linuxLibrary
+	for _, library := range linuxLibraries {
+		if library.Since == "" || release >= library.Since {
+			libraries = append(libraries, library)
+		}
+	}
+	return libraries
+}
+
+// Every soname the dynamic linker knows about, from ldconfig's cache. Returns false if ldconfig couldn't be run.
+func dynamicLinkerCache() (map[string]bool, bool) {
+	ldconfig, err := exec.LookPath("ldconfig")
+	if err != nil {
+		ldconfig = "/sbin/ldconfig" // It's often not on regular users' PATH.
+	}
+	output, err := exec.Command(ldconfig, "-p").Output()
+	if err != nil {
+		return nil, false
+	}
+	sonames := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.Contains(fields[0], ".so") {
+			sonames[fields[0]] = true
+		}
+	}
+	return sonames, true
+}
+
+// Check which of a release's libraries are missing.
+func missingLinuxLibraries(release string) []linuxLibrary {
+	cache, cacheAvailable := dynamicLinkerCache()
+	var missing []linuxLibrary
+	for _, library := range linuxLibrariesFor(release) {
+		found := false
+		switch {
+		case library.Soname == "":
+			for _, pattern := range library.Globs {
+				if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+					found = true
+					break
+				}
+			}
+		case cacheAvailable:
+			found = cache[library.Soname]
+		default:
+			for _, directory := range linuxLibraryDirectories {
+				if _, err := os.Stat(filepath.Join(directory, library.Soname)); err == nil {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			missing = append(missing, library)
+		}
+	}
+	return missing
+}
+
+// The command that installs packages on the distro described by /etc/os-release, and which of each library's package names it uses.
+// Both are empty if we don't recognize the distro.
+func packageInstallCommand(osRelease map[string]string) (string, func(linuxLibrary) string) {
+	families := " " + strings.ToLower(osRelease["ID"]+" "+osRelease["ID_LIKE"]) + " "
+	switch {
+	case strings.Contains(families, " debian ") || strings.Contains(families, " ubuntu "):
+		renamed := debianUsesT64Names(osRelease)
+		return "sudo apt-get install -y", func(library linuxLibrary) string {
+			if renamed && library.RenamedT64 {
+				return library.Debian + "t64"
+			}
+			return library.Debian
+		}
+	case strings.Contains(families, " rhel ") || strings.Contains(families, " fedora ") || strings.Contains(families, " centos "):
+		return "sudo dnf install -y", func(library linuxLibrary) string { return library.RedHat }
+	case strings.Contains(families, " suse ") || strings.Contains(families, " opensuse ") || strings.Contains(families, " sles "):
+		return "sudo zypper install -y", func(library linuxLibrary) string { return library.SUSE }
+	}
+	return "", nil
+}
+
+// Ubuntu 24.04 and Debian 13 renamed some libraries' packages when they moved to 64-bit time.
+func debianUsesT64Names(osRelease map[string]string) bool {
+	version, err := strconv.ParseFloat(osRelease["VERSION_ID"], 64)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(osRelease["ID"]) {
+	case "ubuntu":
+		return version >= 24.04
+	case "debian":
+		return version >= 13
+	}
+	return false
+}
+
+// Look for missing libraries before installing and explain how to get them. Returns the missing libraries' names.
+func checkLinuxLibraries(release string) []string {
+	greenText := color.New(color.FgHiGreen).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Println("Checking for the system libraries " + release + " needs, please wait.")
+	missing := missingLinuxLibraries(release)
+	if len(missing) == 0 {
+		fmt.Println(greenText("All of the system libraries " + release + " needs are installed."))
+		return nil
+	}
+
+	fmt.Println(yellowText("The following system libraries " + release + " needs are missing. MATLAB may not start without them:"))
+	var names []string
+	for _, library := range missing {
+		name := library.Soname
+		if name == "" {
+			name = "fonts"
+		}
+		names = append(names, name)
+		fmt.Println(yellowText("- " + name))
+	}
+
+	osRelease, _ := readOSRelease("/etc/os-release")
+	installCommand, packageName := packageInstallCommand(osRelease)
+	if installCommand == "" {
+		fmt.Println(yellowText("Your distribution isn't one we know the package names for, so you'll need to find the packages that provide these yourself."))
+		return names
+	}
+	var packages []string
+	for _, library := range missing {
+		packages = appendUnique(packages, packageName(library))
+	}
+	fmt.Println(yellowText("You can install them with:"))
+	fmt.Println("    " + installCommand + " " + strings.Join(packages, " "))
+	return names
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// One row of the inventory printed by list-installs.
+type inventoryEntry struct {
+	Host      string   `json:"host"`
+	Path      string   `json:"path"`
+	Release   string   `json:"release"`
+	Update    string   `json:"update"`
+	Products  []string `json:"products"`
+	SizeBytes int64    `json:"sizeBytes"`
+}
+
+// Print every installation we can find on this machine as a table, JSON, or CSV.
+// Usage: list-installs [--format table|json|csv] [--root <dir>]... [--output <file>]
+func listInstallations(args []string) error {
+	format := "table"
+	if formats := argumentValues(args, "--format"); len(formats) > 0 {
+		format = strings.ToLower(formats[len(formats)-1])
+	}
+	if format != "table" && format != "json" && format != "csv" {
+		return fmt.Errorf("unrecognized format \"%s\". Valid formats are table, json, and csv", format)
+	}
+
+	patterns := knownInstallationPatterns(args)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	var inventory []inventoryEntry
+	for _, found := range findInstallations(patterns) {
+		size, err := directorySize(found.Path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Could not calculate the size of \""+found.Path+"\": "+err.Error())
+		}
+		inventory = append(inventory, inventoryEntry{
+			Host:      hostname,
+			Path:      found.Path,
+			Release:   found.Release,
+			Update:    found.Update,
+			Products:  found.Products,
+			SizeBytes: size,
+		})
+	}
+
+	var output io.Writer = os.Stdout
+	if outputPaths := argumentValues(args, "--output"); len(outputPaths) > 0 {
+		file, err := os.Create(outputPaths[len(outputPaths)-1])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		if inventory == nil {
+			inventory = []inventoryEntry{} // Print [] instead of null.
+		}
+		return encoder.Encode(inventory)
+
+	case "csv":
+		writer := csv.NewWriter(output)
+		writer.Write([]string{"host", "path", "release", "update", "size_bytes", "products"})
+		for _, entry := range inventory {
+			writer.Write([]string{entry.Host, entry.Path, entry.Release, entry.Update, strconv.FormatInt(entry.SizeBytes, 10), strings.Join(entry.Products, " ")})
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default:
+		if len(inventory) == 0 {
+			fmt.Fprintln(output, "No installations were found.")
+			return nil
+		}
+		writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "RELEASE\tUPDATE\tSIZE\tPATH\tPRODUCTS")
+		for _, entry := range inventory {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", entry.Release, entry.Update, formatBytes(entry.SizeBytes), entry.Path, strings.Join(entry.Products, " "))
+		}
+		return writer.Flush()
+	}
+}
+
+// Everywhere installations might be. Default locations first, then anything you've told us about with --root or MPM_INSTALL_ROOTS,
+// then whatever this program installed itself.
+func knownInstallationPatterns(args []string) []string {
+	patterns := defaultInstallationRoots(runtime.GOOS)
+	extraRoots := argumentValues(args, "--root")
+	if envRoots := os.Getenv("MPM_INSTALL_ROOTS"); envRoots != "" {
+		extraRoots = append(extraRoots, filepath.SplitList(envRoots)...)
+	}
+	for _, root := range extraRoots {
+		patterns = append(patterns, root, filepath.Join(root, "*"))
+	}
+	return append(patterns, recordedInstallations()...)
+}
+
+// Where we keep track of things between runs, such as the installations this program has made.
+func stateDirectory() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "MPM.Go"), nil
+}
+
+// Remember an installation so list-installs can find it later, even if it's not in a default location.
+func recordInstallation(installPath string) error {
+	fullPath, err := filepath.Abs(installPath)
+	if err != nil {
+		return err
+	}
+	for _, recorded := range recordedInstallations() {
+		if recorded == fullPath {
+			return nil
+		}
+	}
+
+	stateDir, err := stateDirectory()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(stateDir, "installations.txt"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintln(file, fullPath)
+	return err
+}
+
+// The installations this program has made before. Ones that have since been deleted are still listed, so check before using them.
+func recordedInstallations() []string {
+	stateDir, err := stateDirectory()
+	if err != nil {
+		return nil
+	}
+	file, err := os.Open(filepath.Join(stateDir, "installations.txt"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var recorded []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			recorded = append(recorded, line)
+		}
+	}
+	return recorded
+}
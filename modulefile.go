@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Where modulefiles go if you don't say otherwise: the first place Environment Modules or Lmod already looks, or the usual system location.
+func defaultModulefilesTree() string {
+	for _, path := range filepath.SplitList(os.Getenv("MODULEPATH")) {
+		if path != "" {
+			return path
+		}
+	}
+	return "/usr/share/modulefiles"
+}
+
+// The value MLM_LICENSE_FILE should have for a network license, either from servers entered at the license prompt or from a
+// license file's SERVER lines. Empty if the license isn't a network license.
+func networkLicenseSetting(licenseServers string, lic licenseFile) string {
+	if licenseServers != "" {
+		return licenseServers
+	}
+	var addresses []string
+	for _, server := range lic.Servers {
+
+		// Leaving out the port tells FlexNet to try 27000-27009, which is what the license file asks for too.
+		if server.Port == 0 {
+			addresses = append(addresses, "@"+server.Host)
+		} else {
+			addresses = append(addresses, strconv.Itoa(server.Port)+"@"+server.Host)
+		}
+	}
+	return strings.Join(addresses, ",")
+}
+
+// Write a modulefile for matlab/<release> so it can be loaded with "module load". format is either lua (for Lmod) or tcl.
+// Returns where it was written.
+func writeModulefile(tree string, format string, plan installPlan, licenseSetting string) (string, error) {
+	binPath := filepath.Join(plan.Destination, "bin")
+
+	var contents strings.Builder
+	var modulefilePath string
+	switch strings.ToLower(format) {
+	case "lua":
+		modulefilePath = filepath.Join(tree, "matlab", plan.Release+".lua")
+		contents.WriteString("-- matlab/" + plan.Release + ", created by MPM.Go.\n")
+		contents.WriteString("whatis(" + strconv.Quote("Name: MATLAB") + ")\n")
+		contents.WriteString("whatis(" + strconv.Quote("Version: "+plan.Release) + ")\n")
+		contents.WriteString("help(" + strconv.Quote("MATLAB "+plan.Release+", installed in "+plan.Destination) + ")\n")
+		contents.WriteString("conflict(\"matlab\")\n")
+		contents.WriteString("setenv(\"MATLAB_ROOT\", " + strconv.Quote(plan.Destination) + ")\n")
+		contents.WriteString("prepend_path(\"PATH\", " + strconv.Quote(binPath) + ")\n")
+		if licenseSetting != "" {
+			contents.WriteString("setenv(\"MLM_LICENSE_FILE\", " + strconv.Quote(licenseSetting) + ")\n")
+		}
+	case "tcl":
+		modulefilePath = filepath.Join(tree, "matlab", plan.Release)
+		contents.WriteString("#%Module1.0\n")
+		contents.WriteString("## matlab/" + plan.Release + ", created by MPM.Go.\n")
+		contents.WriteString("module-whatis " + tclQuote("MATLAB "+plan.Release) + "\n")
+		contents.WriteString("conflict matlab\n")
+		contents.WriteString("setenv MATLAB_ROOT " + tclQuote(plan.Destination) + "\n")
+		contents.WriteString("prepend-path PATH " + tclQuote(binPath) + "\n")
+		if licenseSetting != "" {
+			contents.WriteString("setenv MLM_LICENSE_FILE " + tclQuote(licenseSetting) + "\n")
+		}
+	default:
+		return "", fmt.Errorf("unknown modulefile format \"%s\". Use lua or tcl", format)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(modulefilePath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(modulefilePath, []byte(contents.String()), 0644); err != nil {
+		return "", err
+	}
+	return modulefilePath, nil
+}
+
+// Quote a value for Tcl so spaces, dollar signs, and brackets are taken literally.
+func tclQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, `[`, `\[`, `]`, `\]`)
+	return "\"" + replacer.Replace(value) + "\""
+}
@@ -11,11 +11,14 @@ import (
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	readline "github.com/Jestzer/readlineJestzer"
 	"github.com/fatih/color"
 )
 
+const versionNumber = "1.5"
+
 // Used to read the output of MPM.
 type customWriter struct {
 	writer io.Writer
@@ -24,36 +27,93 @@ type customWriter struct {
 func main() {
 
 	var (
-		defaultTMP              string
-		installPath             string
-		mpmDownloadPath         string
-		mpmURL                  string
-		products                []string
-		release                 string
-		validReleases           []string
-		defaultInstallationPath string
-		licenseFileUsed         bool
-		licensePath             string
-		mpmFullPath             string
-		newProductsToAdd        map[string]string
-		oldProductsToAdd        map[string]string
-		allProducts             []string
+		defaultTMP      string
+		installPath     string
+		mpmDownloadPath string
+		mpmURL          string
+		products        []string
+		release         string
+		validReleases   []string
+		licenseFileUsed bool
+		licensePaths    []string
+		licenseServers  string
+		mpmFullPath     string
+		allProducts     []string
 	)
 
 	// Print version number, if requested.
 	args := os.Args[1:]
 	for _, arg := range args {
 		if arg == "-version" {
-			fmt.Println("Version number: 1.5")
+			fmt.Println("Version number: " + versionNumber)
 			os.Exit(0)
 		}
 	}
 
+	// Figure out what you'd like to do. Installing is the default.
+	command := "install"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = strings.ToLower(args[0])
+	}
+	exportFormat := ""
+	switch command {
+	case "install", "uninstall", "download":
+	case "export":
+		if len(args) > 1 {
+			exportFormat = strings.ToLower(args[1])
+		}
+		if !isContainerFormat(exportFormat) && !isScriptFormat(exportFormat) {
+			fmt.Println("Enter what you'd like to export: \"" + strings.Join(append(containerFormats, scriptFormats...), "\", \"") + "\". For example, \"export dockerfile\".")
+			os.Exit(1)
+		}
+	case "list-installs":
+		err := listInstallations(args[1:])
+		if err != nil {
+			fmt.Println("Error listing installations: " + err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "use":
+		err := useRelease(args[1:])
+		if err != nil {
+			fmt.Println("Error: " + err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	default:
+		fmt.Println("Unrecognized command: \"" + args[0] + "\". Valid commands are \"install\", \"uninstall\", \"download\", \"list-installs\", \"use\", and \"export\".")
+		os.Exit(1)
+	}
+
+	// Offline installs use installation files downloaded ahead of time with the "download" command.
+	sourcePath := ""
+	if sources := argumentValues(args, "--source"); len(sources) > 0 {
+		if command != "install" {
+			fmt.Println("\"--source\" can only be used when installing.")
+			os.Exit(1)
+		}
+		sourcePath = sources[len(sources)-1]
+	}
+
+	// Shared installations may need to belong to a particular group. Check this now rather than after a long install.
+	permissions, permissionsRequested, err := parsePermissionPolicy(args)
+	if err == nil && permissionsRequested && command != "install" {
+		err = fmt.Errorf("\"--owner\", \"--group\", and \"--mode-policy\" can only be used when installing")
+	}
+	if err != nil {
+		fmt.Println("Error: " + err.Error())
+		os.Exit(1)
+	}
+
 	var mpmDownloadNeeded bool = true
 	var mpmTypeIsMismatched bool = false
 	platform := runtime.GOOS
 	redText := color.New(color.FgRed).SprintFunc()
 	greenText := color.New(color.FgHiGreen).SprintFunc()
+	yellowText := color.New(color.FgYellow).SprintFunc()
+
+	// Everything worth warning about along the way, so it ends up in the installation report too.
+	var warnings []string
 
 	// Reader to make using the command line not suck.
 	rl, err := readline.NewEx(&readline.Config{
@@ -147,8 +207,25 @@ func main() {
 		ExitHelper()
 	}
 
-	// Figure out where you want actual MPM to go.
-	for {
+	// Check the offline bundle before anything else so a bad one is caught right away.
+	var bundle *bundleManifest
+	if sourcePath != "" {
+		manifest, err := loadOfflineBundle(sourcePath, platform)
+		if err != nil {
+			fmt.Println(redText("Error using the offline bundle in \""+sourcePath+"\": ", err, ". Press Enter/Return on your keyboard to close this program."))
+			ExitHelper()
+		}
+		bundle = &manifest
+		fmt.Println("Using the offline bundle for " + bundle.Release + " in \"" + sourcePath + "\". Nothing will be downloaded.")
+	}
+
+	// Exported scripts download MPM on the machine they're run on, which may not look like this one.
+	if isScriptFormat(exportFormat) {
+		defaultTMP = exportDefaultDownloadPath(exportTargetPlatform(exportFormat, platform))
+	}
+
+	// Figure out where you want actual MPM to go. Offline installs use the copy of MPM in the bundle instead, so this is skipped for them.
+	for bundle == nil && !isContainerFormat(exportFormat) {
 		fmt.Print("Enter the path to where you would like MPM to download to. " +
 			"Press Enter to use \"" + defaultTMP + "\"\n> ")
 		mpmDownloadPath, err = readUserInput(rl)
@@ -165,7 +242,7 @@ func main() {
 
 		if mpmDownloadPath == "" {
 			mpmDownloadPath = defaultTMP
-		} else {
+		} else if exportFormat == "" {
 			_, err := os.Stat(mpmDownloadPath)
 			if os.IsNotExist(err) {
 				fmt.Printf("The directory \"%s\" does not exist. Do you want to create it? (y/n)\n> ", mpmDownloadPath)
@@ -200,6 +277,11 @@ func main() {
 			}
 		}
 
+		// The exported script does the rest.
+		if exportFormat != "" {
+			break
+		}
+
 		// Check if MPM already exists in the selected directory.
 		fileName := filepath.Join(mpmDownloadPath, "mpm")
 		if platform == "windows" {
@@ -302,19 +384,67 @@ func main() {
 		break
 	}
 
-	// Ask the user which release they'd like to install.
-	if platform == "macOSARM" {
-		validReleases = []string{
-			"R2023b", "R2024a", "R2024b", "R2025a",
-		}
-	} else {
-		validReleases = []string{
-			"R2017b", "R2018a", "R2018b", "R2019a", "R2019b", "R2020a", "R2020b",
-			"R2021a", "R2021b", "R2022a", "R2022b", "R2023a", "R2023b", "R2024a", "R2024b", "R2025a",
+	if runtime.GOOS == "darwin" {
+		mpmFullPath = mpmDownloadPath + "/mpm"
+	}
+	if runtime.GOOS == "windows" {
+		mpmFullPath = mpmDownloadPath + "\\mpm.exe"
+	}
+	if runtime.GOOS == "linux" {
+		mpmFullPath = mpmDownloadPath + "/mpm"
+	}
+	if bundle != nil {
+		mpmFullPath = filepath.Join(sourcePath, filepath.FromSlash(bundle.MPM))
+
+		// Copying a bundle around can lose the executable bit.
+		if platform != "windows" {
+			info, err := os.Stat(mpmFullPath)
+			if err == nil && info.Mode()&0111 == 0 {
+				err = os.Chmod(mpmFullPath, 0755)
+			}
+			if err != nil {
+				fmt.Println(redText("Could not make the bundle's copy of MPM executable: ", err, ". Either copy the bundle somewhere writable or run this program with needed privileges."))
+			}
 		}
 	}
 
+	// Uninstalling doesn't need anything else.
+	if command == "uninstall" {
+		uninstallProducts(rl, mpmFullPath, platform)
+		fmt.Println("Press the Enter/Return key to close this program.")
+		ExitHelper()
+	}
+
+	// Downloads can be for a different platform than the one you're on.
+	targetPlatform := platform
+	if command == "download" {
+		targetPlatform = promptTargetPlatform(rl, platform)
+	}
+
+	// Containers are always Linux, whatever you're building them on.
+	if isContainerFormat(exportFormat) {
+		targetPlatform = "linux"
+	}
+	if isScriptFormat(exportFormat) {
+		targetPlatform = exportTargetPlatform(exportFormat, platform)
+	}
+
+	// Ask the user which release they'd like to install.
+	validReleases = validReleasesFor(targetPlatform)
+
+	// On Linux, suggest the newest release that supports your distribution rather than always the newest release.
 	defaultRelease := "R2025a"
+	var compatibility map[string]releaseCompatibility
+	if command == "install" && exportFormat == "" && platform == "linux" {
+		var newestSupported string
+		compatibility, newestSupported = printReleaseCompatibility(validReleases)
+		if newestSupported != "" {
+			defaultRelease = newestSupported
+		}
+	}
+	if bundle != nil {
+		defaultRelease = bundle.Release
+	}
 
 	for {
 		fmt.Printf("Enter which release you would like to install. Press Enter to select %s: ", defaultRelease)
@@ -345,17 +475,40 @@ func main() {
 			}
 		}
 
+		if found && bundle != nil && release != bundle.Release {
+			fmt.Println(redText("Your offline bundle only contains " + bundle.Release + ". Either enter " + bundle.Release + " or use a different bundle."))
+			continue
+		}
+
+		if found && compatibility[release].Status == "unsupported" {
+			if !promptYesNo(rl, release+" isn't supported on this system ("+compatibility[release].Reason+"). Would you like to install it anyway?") {
+				continue
+			}
+			warnings = append(warnings, release+" was installed even though it isn't supported on this system ("+compatibility[release].Reason+").")
+		}
+
 		if found {
 			break
 		}
 
-		if platform == "macOSARM" {
+		if targetPlatform == "macOSARM" {
 			fmt.Println(redText("Invalid release. Enter a release between R2023b-R2025a."))
 		} else {
 			fmt.Println(redText("Invalid release. Enter a release between R2017b-R2025a."))
 		}
 	}
 
+	// Missing system libraries are much easier to sort out now than after MATLAB refuses to start.
+	if command == "install" && exportFormat == "" && platform == "linux" {
+		if missingLibraries := checkLinuxLibraries(release); len(missingLibraries) > 0 {
+			if !promptYesNo(rl, "Would you like to continue installing anyway?") {
+				fmt.Println("Install the missing libraries and run this program again. Press the Enter/Return key to close this program.")
+				ExitHelper()
+			}
+			warnings = append(warnings, "Installed without these system libraries: "+strings.Join(missingLibraries, ", "))
+		}
+	}
+
 	for {
 		// Product selection.
 		fmt.Print("Enter the products you would like to install. Use the same syntax as MPM to specify products. " +
@@ -373,131 +526,28 @@ func main() {
 
 		productsInput = strings.TrimSpace(productsInput)
 
-		// Begin assembling the full product list based on your release and platform.
+		// Assemble the full product list based on your release and platform.
 		// This is to ensure the products you're specifying exist or that a full list is assembled if you decide to install everything.
-		// Notes:
-		// - No oldProductsToAdd is needed for macOSARM at the moment.
-		// - No new products were added in R2024a, R2024b, nor R2025a for any platform, so they are ommitted entries.
-
-		// Let's start with defining the "new" products to add.
-		switch platform {
-		case "windows":
-			newProductsToAdd = map[string]string{
-				"R2023b": "Simulink_Fault_Analyzer Polyspace_Test",
-				"R2023a": "MATLAB_Test C2000_Microcontroller_Blockset",
-				"R2022b": "Medical_Imaging_Toolbox Simscape_Battery",
-				"R2022a": "Wireless_Testbench Bluetooth_Toolbox DSP_HDL_Toolbox Requirements_Toolbox Industrial_Communication_Toolbox",
-				"R2021b": "Signal_Integrity_Toolbox RF_PCB_Toolbox",
-				"R2021a": "Satellite_Communications_Toolbox DDS_Blockset",
-				"R2020b": "UAV_Toolbox Radar_Toolbox Lidar_Toolbox Deep_Learning_HDL_Toolbox",
-				"R2020a": "Simulink_Compiler Motor_Control_Blockset MATLAB_Web_App_Server Wireless_HDL_Toolbox",
-				"R2019b": "ROS_Toolbox Navigation_Toolbox",
-				"R2019a": "System_Composer SoC_Blockset SerDes_Toolbox Reinforcement_Learning_Toolbox Audio_Toolbox Mixed-Signal_Blockset AUTOSAR_Blockset MATLAB_Parallel_Server Polyspace_Bug_Finder_Server Polyspace_Code_Prover_Server Automated_Driving_Toolbox Computer_Vision_Toolbox",
-				"R2018b": "Communications_Toolbox Simscape_Electrical Sensor_Fusion_and_Tracking_Toolbox Deep_Learning_Toolbox 5G_Toolbox WLAN_Toolbox LTE_Toolbox",
-				"R2018a": "Predictive_Maintenance_Toolbox Vehicle_Dynamics_Blockset",
-				"R2017b": "Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Bioinformatics_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DSP_System_Toolbox Data_Acquisition_Toolbox Database_Toolbox Datafeed_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox GPU_Coder Global_Optimization_Toolbox HDL_Coder HDL_Verifier Image_Acquisition_Toolbox Image_Processing_Toolbox Instrument_Control_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Production_Server MATLAB_Report_Generator Mapping_Toolbox Model_Predictive_Control_Toolbox Model-Based_Calibration_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Polyspace_Bug_Finder Polyspace_Code_Prover Powertrain_Blockset RF_Blockset RF_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Driveline Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Desktop_Real-Time Simulink_PLC_Coder Simulink_Real-Time Simulink_Report_Generator Simulink_Test Spreadsheet_Link Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Identification_Toolbox Text_Analytics_Toolbox Vehicle_Network_Toolbox Vision_HDL_Toolbox Wavelet_Toolbox",
-			}
-
-		case "linux":
-			newProductsToAdd = map[string]string{
-				"R2023b": "Simulink_Fault_Analyzer Polyspace_Test Simulink_Desktop_Real-Time",
-				"R2023a": "MATLAB_Test C2000_Microcontroller_Blockset",
-				"R2022b": "Medical_Imaging_Toolbox Simscape_Battery",
-				"R2022a": "Wireless_Testbench Simulink_Real-Time Bluetooth_Toolbox DSP_HDL_Toolbox Requirements_Toolbox Industrial_Communication_Toolbox",
-				"R2021b": "Signal_Integrity_Toolbox RF_PCB_Toolbox",
-				"R2021a": "Satellite_Communications_Toolbox DDS_Blockset",
-				"R2020b": "UAV_Toolbox Radar_Toolbox Lidar_Toolbox Deep_Learning_HDL_Toolbox",
-				"R2020a": "Simulink_Compiler Motor_Control_Blockset MATLAB_Web_App_Server Wireless_HDL_Toolbox",
-				"R2019b": "ROS_Toolbox Simulink_PLC_Coder Navigation_Toolbox",
-				"R2019a": "System_Composer SoC_Blockset SerDes_Toolbox Reinforcement_Learning_Toolbox Audio_Toolbox Mixed-Signal_Blockset AUTOSAR_Blockset MATLAB_Parallel_Server Polyspace_Bug_Finder_Server Polyspace_Code_Prover_Server Automated_Driving_Toolbox Computer_Vision_Toolbox",
-				"R2018b": "Communications_Toolbox Simscape_Electrical Sensor_Fusion_and_Tracking_Toolbox Deep_Learning_Toolbox 5G_Toolbox WLAN_Toolbox LTE_Toolbox",
-				"R2018a": "Predictive_Maintenance_Toolbox Vehicle_Network_Toolbox Vehicle_Dynamics_Blockset",
-				"R2017b": "Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Bioinformatics_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DSP_System_Toolbox Database_Toolbox Datafeed_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox GPU_Coder Global_Optimization_Toolbox HDL_Coder HDL_Verifier Image_Acquisition_Toolbox Image_Processing_Toolbox Instrument_Control_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Production_Server MATLAB_Report_Generator Mapping_Toolbox Model_Predictive_Control_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Polyspace_Bug_Finder Polyspace_Code_Prover Powertrain_Blockset RF_Blockset RF_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Driveline Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Report_Generator Simulink_Test Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Identification_Toolbox Text_Analytics_Toolbox Vision_HDL_Toolbox Wavelet_Toolbox",
-			}
-
-		case "macOSx64":
-			newProductsToAdd = map[string]string{
-				"R2023b": "Simulink_Fault_Analyzer Polyspace_Test",
-				"R2023a": "MATLAB_Test",
-				"R2022b": "Medical_Imaging_Toolbox Simscape_Battery",
-				"R2022a": "Bluetooth_Toolbox DSP_HDL_Toolbox Requirements_Toolbox Industrial_Communication_Toolbox",
-				"R2021b": "RF_PCB_Toolbox",
-				"R2021a": "Satellite_Communications_Toolbox DDS_Blockset",
-				"R2020b": "UAV_Toolbox Radar_Toolbox Lidar_Toolbox",
-				"R2020a": "Simulink_Compiler Motor_Control_Blockset MATLAB_Web_App_Server Wireless_HDL_Toolbox",
-				"R2019b": "ROS_Toolbox Simulink_PLC_Coder Navigation_Toolbox",
-				"R2019a": "System_Composer SerDes_Toolbox Reinforcement_Learning_Toolbox Audio_Toolbox Mixed-Signal_Blockset AUTOSAR_Blockset Polyspace_Bug_Finder_Server Polyspace_Code_Prover_Server Automated_Driving_Toolbox Computer_Vision_Toolbox",
-				"R2018b": "Communications_Toolbox Simscape_Electrical Sensor_Fusion_and_Tracking_Toolbox Deep_Learning_Toolbox 5G_Toolbox WLAN_Toolbox LTE_Toolbox",
-				"R2018a": "Predictive_Maintenance_Toolbox Vehicle_Dynamics_Blockset",
-				"R2017b": "Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Bioinformatics_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DSP_System_Toolbox Database_Toolbox Datafeed_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox Global_Optimization_Toolbox HDL_Coder Image_Acquisition_Toolbox Image_Processing_Toolbox Instrument_Control_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Production_Server MATLAB_Report_Generator Mapping_Toolbox Model_Predictive_Control_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Polyspace_Bug_Finder Polyspace_Code_Prover Powertrain_Blockset RF_Blockset RF_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Driveline Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Desktop_Real-Time Simulink_Report_Generator Simulink_Test Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Identification_Toolbox Text_Analytics_Toolbox Wavelet_Toolbox",
-			}
-
-		case "macOSARM":
-			newProductsToAdd = map[string]string{
-				"R2023b": "5G_Toolbox AUTOSAR_Blockset Aerospace_Blockset Aerospace_Toolbox Antenna_Toolbox Audio_Toolbox Automated_Driving_Toolbox Bioinformatics_Toolbox Bluetooth_Toolbox Communications_Toolbox Computer_Vision_Toolbox Control_System_Toolbox Curve_Fitting_Toolbox DDS_Blockset DSP_HDL_Toolbox DSP_System_Toolbox Database_Toolbox Datafeed_Toolbox Deep_Learning_Toolbox Econometrics_Toolbox Embedded_Coder Financial_Instruments_Toolbox Financial_Toolbox Fixed-Point_Designer Fuzzy_Logic_Toolbox Global_Optimization_Toolbox HDL_Coder Image_Acquisition_Toolbox Image_Processing_Toolbox Industrial_Communication_Toolbox Instrument_Control_Toolbox LTE_Toolbox Lidar_Toolbox MATLAB MATLAB_Coder MATLAB_Compiler MATLAB_Compiler_SDK MATLAB_Report_Generator MATLAB_Test Mapping_Toolbox Medical_Imaging_Toolbox Mixed-Signal_Blockset Model_Predictive_Control_Toolbox Motor_Control_Blockset Navigation_Toolbox Network_License_Manager Optimization_Toolbox Parallel_Computing_Toolbox Partial_Differential_Equation_Toolbox Phased_Array_System_Toolbox Powertrain_Blockset Predictive_Maintenance_Toolbox RF_Blockset RF_PCB_Toolbox RF_Toolbox ROS_Toolbox Radar_Toolbox Reinforcement_Learning_Toolbox Requirements_Toolbox Risk_Management_Toolbox Robotics_System_Toolbox Robust_Control_Toolbox Satellite_Communications_Toolbox Sensor_Fusion_and_Tracking_Toolbox SerDes_Toolbox Signal_Processing_Toolbox SimBiology SimEvents Simscape Simscape_Battery Simscape_Driveline Simscape_Electrical Simscape_Fluids Simscape_Multibody Simulink Simulink_3D_Animation Simulink_Check Simulink_Coder Simulink_Compiler Simulink_Control_Design Simulink_Coverage Simulink_Design_Optimization Simulink_Design_Verifier Simulink_Fault_Analyzer Simulink_PLC_Coder Simulink_Report_Generator Simulink_Test Stateflow Statistics_and_Machine_Learning_Toolbox Symbolic_Math_Toolbox System_Composer System_Identification_Toolbox Text_Analytics_Toolbox UAV_Toolbox Vehicle_Dynamics_Blockset WLAN_Toolbox Wavelet_Toolbox Wireless_HDL_Toolbox",
-			}
-		}
-
-		// Use a loop to go through the list above to add the appropriate products.
-		for releaseLoop, product := range newProductsToAdd {
-			if release >= releaseLoop {
-				allProducts = append(allProducts, strings.Fields(product)...)
-			}
-		}
-
-		// Old products to add.
-		switch platform {
-		case "windows":
-			oldProductsToAdd = map[string]string{
-				"R2024b": "Filter_Design_HDL_Coder",
-				"R2021b": "Simulink_Requirements OPC_Toolbox",
-				"R2020b": "Trading_Toolbox",
-				"R2019b": "LTE_HDL_Toolbox",
-				"R2018b": "Audio_System_Toolbox Automated_Driving_System_Toolbox Computer_Vision_System_Toolbox MATLAB_Distributed_Computing_Server",
-				"R2018a": "Communications_System_Toolbox LTE_System_Toolbox Neural_Network_Toolbox Simscape_Electronics Simscape_Power_Systems WLAN_System_Toolbox",
-			}
-
-		case "linux":
-			oldProductsToAdd = map[string]string{
-				"R2024b": "Filter_Design_HDL_Coder",
-				"R2021b": "Simulink_Requirements",
-				"R2020b": "Trading_Toolbox",
-				"R2019b": "LTE_HDL_Toolbox",
-				"R2018b": "Audio_System_Toolbox Automated_Driving_System_Toolbox Computer_Vision_System_Toolbox MATLAB_Distributed_Computing_Server",
-				"R2018a": "Communications_System_Toolbox LTE_System_Toolbox Neural_Network_Toolbox Simscape_Electronics Simscape_Power_Systems WLAN_System_Toolbox",
-			}
-
-		case "macOSx64":
-			oldProductsToAdd = map[string]string{
-				"R2024b": "Filter_Design_HDL_Coder",
-				"R2021b": "Simulink_Requirements MATLAB_Parallel_Server",
-				"R2020b": "Trading_Toolbox",
-				"R2019b": "LTE_HDL_Toolbox",
-				"R2018b": "Audio_System_Toolbox Automated_Driving_System_Toolbox Computer_Vision_System_Toolbox MATLAB_Distributed_Computing_Server",
-				"R2018a": "Communications_System_Toolbox LTE_System_Toolbox Neural_Network_Toolbox Simscape_Electronics Simscape_Power_Systems WLAN_System_Toolbox",
-			}
-		case "macOSARM":
-			oldProductsToAdd = map[string]string{
-				"R2024b": "Filter_Design_HDL_Coder",
-			}
-		}
-
-		// The actual for loop that goes through the list above. Note that it uses the same logic as newProducts, it just uses <= instead of >=.
-		for releaseLoop, product := range oldProductsToAdd {
-			if release <= releaseLoop {
-				allProducts = append(allProducts, strings.Fields(product)...)
-			}
-		}
+		allProducts = productsForRelease(targetPlatform, release)
 
 		// Determine the products we'll actually be using with MPM.
 		if productsInput == "" {
 			products = allProducts
-		} else if productsInput == "parallel_products" && release != "R2018b" && release != "R2018a" && release != "R2017b" {
-			products = []string{"MATLAB", "Parallel_Computing_Toolbox", "MATLAB_Parallel_Server"}
-		} else if productsInput == "parallel_products" && release == "R2018b" || release == "R2018a" || release == "R2017b" {
-			products = []string{"MATLAB", "Parallel_Computing_Toolbox", "MATLAB_Parallel_Server"}
+			if bundle != nil {
+				products = bundle.Products
+			}
+		} else if productsInput == "parallel_products" {
+
+			// R2018b and older call MATLAB_Parallel_Server MATLAB_Distributed_Computing_Server, which the aliases take care of.
+			products, _ = resolveProductAliases([]string{"MATLAB", "Parallel_Computing_Toolbox", "MATLAB_Parallel_Server"}, allProducts, release)
 		} else {
-			products = strings.Fields(productsInput)
+			// Old scripts and habits use names from other releases, so translate those rather than call them missing.
+			var notices []string
+			products, notices = resolveProductAliases(strings.Fields(productsInput), allProducts, release)
+			for _, notice := range notices {
+				fmt.Println(yellowText(notice))
+			}
+			warnings = append(warnings, notices...)
 			missingProducts := checkProductsExist(products, allProducts)
 			if len(missingProducts) > 0 {
 				fmt.Println(redText("The following products do not exist:"))
@@ -508,72 +558,75 @@ func main() {
 				continue
 			}
 		}
+
+		// Offline installs can only use what was downloaded.
+		if bundle != nil {
+			missingProducts := checkProductsExist(products, bundle.Products)
+			if len(missingProducts) > 0 {
+				fmt.Println(redText("The following products are not in your offline bundle:"))
+				for _, missingProduct := range missingProducts {
+					fmt.Println(redText("- " + missingProduct))
+				}
+				fmt.Println(redText("Please try again with products from the bundle, or create a new bundle that includes them."))
+				continue
+			}
+		}
 		break
 	}
 
-	// Set the default installation path based on your OS.
-	if platform == "macOSx64" || platform == "macOSARM" {
-		defaultInstallationPath = "/Applications/MATLAB_" + release + ".app"
-	}
-	if platform == "windows" {
-		defaultInstallationPath = "C:\\Program Files\\MATLAB\\" + release
+	// Let you know if you're about to build on something that won't be around next release. The license step can still add
+	// products, so installs check once that's done and only downloads and containers, which skip it, check now.
+	warnAboutFinalReleaseProducts := func() {
+		finalProducts := productsInFinalRelease(targetPlatform, release, products)
+		if len(finalProducts) == 0 {
+			return
+		}
+		fmt.Println(yellowText(release + " is the last release that includes the following products:"))
+		for _, product := range finalProducts {
+			if replacement, found := productReplacements[product]; found {
+				fmt.Println(yellowText("- " + product + ", which is replaced by " + replacement))
+				warnings = append(warnings, release+" is the last release with "+product+", which is replaced by "+replacement+".")
+			} else {
+				fmt.Println(yellowText("- " + product + ", which has no replacement"))
+				warnings = append(warnings, release+" is the last release with "+product+", which has no replacement.")
+			}
+		}
+		fmt.Println(yellowText("Newer releases won't be able to install these, so plan on moving away from them."))
 	}
-	if platform == "linux" {
-		defaultInstallationPath = "/usr/local/MATLAB/" + release
+	if command == "download" || isContainerFormat(exportFormat) {
+		warnAboutFinalReleaseProducts()
 	}
 
-	for {
-		fmt.Print("Enter the full path where you would like to install these products. "+
-			"Press Enter to install to default path: \"", defaultInstallationPath, "\"\n> ")
-
-		installPath, err = readUserInput(rl)
+	// Downloading stops here, since nothing is being installed.
+	if command == "download" {
+		err := createOfflineBundle(rl, mpmFullPath, targetPlatform, release, products)
 		if err != nil {
-			if err.Error() == "Interrupt" {
-				fmt.Println(redText("Exiting from user input."))
-			} else {
-				fmt.Println(redText("Error reading line: ", err))
-				continue
-			}
-			return
+			fmt.Println(redText("Error creating the offline bundle: ", err, ". Press the Enter/Return key to close this program."))
+		} else {
+			fmt.Println(greenText("Download finished! Press the Enter/Return key to close this program."))
 		}
+		ExitHelper()
+	}
 
-		installPath = strings.TrimSpace(installPath)
-
-		if installPath == "" {
-			installPath = defaultInstallationPath
+	if isContainerFormat(exportFormat) {
+		exportPath, err := exportContainerRecipe(rl, args, exportFormat, release, products)
+		if err != nil {
+			fmt.Println(redText("Error exporting: ", err, ". Press the Enter/Return key to close this program."))
 		} else {
-			if _, err := os.Stat(installPath); os.IsNotExist(err) {
-
-				// If the folder does not exist, try to create it.
-				if _, err := os.Stat(installPath); os.IsNotExist(err) {
-					if err := os.MkdirAll(installPath, 0755); err != nil {
-						fmt.Println(redText("Error creating directory: ", err, " Please pick a different installation path."))
-						continue
-					} else {
-						fullPath, err := filepath.Abs(installPath)
-						if err != nil {
-							fmt.Println(redText("Error reading newly-created directory's full path: ", err, " Please pick a different installation path."))
-							continue
-						} else {
-							fmt.Println("Directory successfully created:", fullPath)
-						}
-					}
-				}
-			} else if err != nil {
-				fullPath, _ := filepath.Abs(installPath)
-				fmt.Println(redText("Error selecting directory: ", fullPath, " Please pick a different installation path."))
-				continue
-			}
+			fmt.Println(greenText("Your " + exportFormat + " has been saved to \"" + exportPath + "\". Press the Enter/Return key to close this program."))
 		}
-		break
+		ExitHelper()
 	}
 
 	// Optional license file selection.
+	var lic licenseFile
+	removeNetworkLicense := func() {} // The network.lic we create is temporary and gets deleted once it's been placed.
 	for {
-		fmt.Print("If you have a license file you'd like to include in your installation, " +
-			"please provide the full path to the existing license file.\n> ")
+		fmt.Print("If you have license files you'd like to include in your installation, " +
+			"please provide the full path to the existing license file. Separate several license files with commas, or provide the folder they're in. " +
+			"If you use a license server, enter it as port@host instead.\n> ")
 
-		licensePath, err = readUserInput(rl)
+		licenseInput, err := readUserInput(rl)
 		if err != nil {
 			if err.Error() == "Interrupt" {
 				fmt.Println(redText("Exiting from user input."))
@@ -583,108 +636,432 @@ func main() {
 			}
 			return
 		}
-		licensePath = strings.TrimSpace(licensePath)
+		licenseInput = strings.TrimSpace(licenseInput)
 
-		if licensePath == "" {
+		if licenseInput == "" {
 			licenseFileUsed = false
 			break
+		} else if looksLikeLicenseServer(licenseInput) {
+
+			// Create network.lic for the servers you entered so you don't have to write it yourself.
+			servers, err := parseLicenseServers(licenseInput)
+			if err != nil {
+				fmt.Println(redText("Error: ", err))
+				continue
+			}
+			licensePath, cleanup, err := createNetworkLicense(servers)
+			if err != nil {
+				fmt.Println(redText("Error creating network license file: ", err))
+				continue
+			}
+			lic, err = validateLicenseFile(licensePath, exportFormat == "")
+			if err != nil {
+				fmt.Println(redText("Error reading network license file: ", err))
+				cleanup()
+				continue
+			}
+
+			// Find out now if the license server can't be reached, rather than when MATLAB first fails to start.
+			// Exported scripts run somewhere else, so whether we can reach it from here doesn't say much.
+			if exportFormat == "" {
+				unreachable := unreachableLicenseServers(lic)
+				if len(unreachable) > 0 && !promptYesNo(rl, "Would you like to use this license server anyway?") {
+					cleanup()
+					continue
+				}
+				lic.Warnings = append(lic.Warnings, unreachable...)
+			}
+			removeNetworkLicense = cleanup
+			licenseServers = licenseServerSetting(servers)
+			fmt.Println("A network license file for " + licenseServers + " will be placed in your installation as network.lic.")
+			licensePaths = []string{licensePath}
+			licenseFileUsed = true
+			break
 		} else {
 
-			// Check if the license file exists and has the correct extension.
-			_, err := os.Stat(licensePath)
+			// Check if the license files exist and have the correct extension.
+			expandedPaths, err := expandLicensePaths(licenseInput)
 			if err != nil {
 				fmt.Println(redText("Error: ", err))
 				continue
-			} else if !strings.HasSuffix(licensePath, ".dat") && !strings.HasSuffix(licensePath, ".lic") && !strings.HasSuffix(licensePath, ".xml") {
-				fmt.Println(redText("Invalid file extension. Please provide a file with a .dat, .lic, or .xml file extension."))
+			}
+			lics, ok := checkLicenseFiles(rl, expandedPaths, exportFormat == "")
+			if !ok {
 				continue
-			} else {
-				licenseFileUsed = true
-				break
 			}
+			lic = mergeLicenses(lics)
+			licensePaths = expandedPaths
+			licenseFileUsed = true
+
+			// Make sure you'll actually be able to use what you're installing.
+			licensedProducts := allProducts
+			if bundle != nil {
+				licensedProducts = bundle.Products
+			}
+			var coverageWarnings []string
+			products, coverageWarnings = adjustProductsForLicense(rl, lic, products, licensedProducts)
+			lic.Warnings = append(lic.Warnings, coverageWarnings...)
+			break
 		}
 	}
+	warnings = append(warnings, lic.Warnings...)
+	warnAboutFinalReleaseProducts()
 
-	fmt.Println("Loading, please wait.")
+	// Shared installations can hold licenses for everyone, but individual licenses belong to one person.
+	var placement licensePlacement
+	var licenses []licenseSource
+	if licenseFileUsed && exportFormat == "" {
+		placement = promptLicensePlacement(rl, lic)
 
-	if runtime.GOOS == "darwin" {
-		mpmFullPath = mpmDownloadPath + "/mpm"
-	}
-	if runtime.GOOS == "windows" {
-		mpmFullPath = mpmDownloadPath + "\\mpm.exe"
-	}
-	if runtime.GOOS == "linux" {
-		mpmFullPath = mpmDownloadPath + "/mpm"
+		// Remember exactly what was checked so we can tell if a license file changes or can't be read by the time it's placed.
+		licenses, err = prepareLicenseSources(licensePaths)
+		if err != nil {
+			removeNetworkLicense()
+			fmt.Println(redText("Error reading license file: ", err, ". Press the Enter/Return key to close this program."))
+			ExitHelper()
+		}
 	}
 
-	// Construct the command and arguments to launch MPM.
-	cmdArgs := []string{
-		mpmFullPath,
-		"install",
-		"--release=" + release,
-		"--destination=" + installPath,
-		"--products",
+	// Polyspace gets installed separately from MATLAB so updating or uninstalling one doesn't affect the other.
+	matlabProducts, polyspaceProducts := splitPolyspaceProducts(products)
+	if len(matlabProducts) > 0 && len(polyspaceProducts) > 0 {
+		fmt.Println("Your selection includes both MATLAB and Polyspace products. They will be installed to separate locations.")
 	}
-	cmdArgs = append(cmdArgs, products...)
 
-	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	var plans []installPlan
+	installSource := ""
+	if bundle != nil {
+		installSource = filepath.Join(sourcePath, bundleArchivesDirectory)
+	}
+	if len(matlabProducts) > 0 {
+		if exportFormat != "" {
+			installPath = promptExportDestination(rl, "Enter the full path where you would like to install these products.", defaultInstallationPath(targetPlatform, release, false))
+		} else {
+			installPath = promptInstallationPath(rl, "Enter the full path where you would like to install these products.", defaultInstallationPath(platform, release, false))
+		}
+		plans = append(plans, installPlan{Release: release, Destination: installPath, Products: matlabProducts, Source: installSource})
+	}
+	if len(polyspaceProducts) > 0 {
+		for {
+			var polyspacePath string
+			if exportFormat != "" {
+				polyspacePath = promptExportDestination(rl, "Enter the full path where you would like to install your Polyspace products.", defaultInstallationPath(targetPlatform, release, true))
+			} else {
+				polyspacePath = promptInstallationPath(rl, "Enter the full path where you would like to install your Polyspace products.", defaultInstallationPath(platform, release, true))
+			}
 
-	// Use customWriter to intercept and process MPM's output.
-	cmd.Stdout = &customWriter{writer: os.Stdout}
-	cmd.Stderr = &customWriter{writer: os.Stderr}
-	err = cmd.Run() // Run it already geeeeeeeez.
+			// Polyspace can technically go inside MATLAB, but then the two can't be updated or removed independently.
+			matlabDestination := ""
+			if len(plans) > 0 {
+				matlabDestination = plans[0].Destination
+			}
+			if insideMATLABRoot(polyspacePath, matlabDestination) {
+				fmt.Println(redText("Warning: \"" + polyspacePath + "\" is inside a MATLAB installation. Installing Polyspace here will make it difficult to update or uninstall either one later."))
+				if !promptYesNo(rl, "Would you like to install Polyspace here anyway?") {
+					continue
+				}
+			}
+			plans = append(plans, installPlan{Release: release, Destination: polyspacePath, Products: polyspaceProducts, Polyspace: true, Source: installSource})
+			break
+		}
+	}
 
-	if err != nil {
-		errString := err.Error()
-		if strings.Contains(errString, "mpm: no such file or directory") || strings.Contains(errString, "mpm.exe: no such file or directory") {
-			fmt.Println(redText("MPM was either moved, renamed, deleted, or you've lost permissions to access it. Press the Enter/Return key to close this program."))
+	// Scripts replay exactly these plans somewhere else instead of running them here.
+	if isScriptFormat(exportFormat) {
+		script := sessionScript{Platform: targetPlatform, MPMDownloadPath: mpmDownloadPath, Plans: plans}
+		exportPath, err := exportSessionScript(rl, args, exportFormat, script, licensePaths)
+		removeNetworkLicense()
+		if err != nil {
+			fmt.Println(redText("Error exporting: ", err, ". Press the Enter/Return key to close this program."))
 		} else {
-			fmt.Println(redText("An error occurred during installation. See the error above for more information. ", err, ". Press the Enter/Return key to close this program."))
+			fmt.Println(greenText("Your " + exportFormat + " script has been saved to \"" + exportPath + "\". Press the Enter/Return key to close this program."))
 		}
 		ExitHelper()
 	}
 
-	// Create the licenses directory and the file specified, if you specified one.
+	fmt.Println("Loading, please wait.")
+
+	// Where each installation's license files ended up, and where they should have. The checklist goes by where they should be,
+	// so a copy that failed shows up there too.
+	placedLicenses := make(map[string][]string)
+	expectedLicenses := make(map[string][]string)
 	if licenseFileUsed {
+		for _, plan := range plans {
+			expectedLicenses[plan.Destination] = expectedLicensePaths(licenses, placement, plan, platform, release)
+		}
+	}
+	started := time.Now()
+
+	// Keep a record of what was done for whoever needs to know later, even if it didn't go well.
+	newReport := func(results []installationReportResult, installError string) installationReport {
+		hostname, _ := os.Hostname()
+		mpmHash, _ := hashFile(mpmFullPath)
+		finished := time.Now()
+		return installationReport{
+			Host:             hostname,
+			OS:               osDescription(),
+			WrapperVersion:   versionNumber,
+			MPMPath:          mpmFullPath,
+			MPMSHA256:        mpmHash,
+			Release:          release,
+			Started:          started,
+			Finished:         finished,
+			DurationSeconds:  int64(finished.Sub(started).Seconds()),
+			Installations:    results,
+			LicensePlacement: describeLicensePlacement(licenseFileUsed, placement),
+			Warnings:         warnings,
+			Error:            installError,
+		}
+	}
+
+	for planIndex, plan := range plans {
+		if len(plans) > 1 {
+			fmt.Println("Installing " + plan.family() + " products to \"" + plan.Destination + "\".")
+		}
 
-		// Create the licenses directory.
-		licensesInstallationDirectory := filepath.Join(installPath, "licenses")
-		err := os.Mkdir(licensesInstallationDirectory, 0755)
+		// Construct the command and arguments to launch MPM.
+		cmdArgs := plan.mpmArgs(mpmFullPath)
+
+		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+
+		// Use customWriter to intercept and process MPM's output.
+		cmd.Stdout = &customWriter{writer: os.Stdout}
+		cmd.Stderr = &customWriter{writer: os.Stderr}
+		err = cmd.Run() // Run it already geeeeeeeez.
 
-		// The licenses directory may already exist if we're installation toolboxes into an existing installation of a base product, in which case, we'll ignore the error produced.
 		if err != nil {
 			errString := err.Error()
-			if !strings.Contains(errString, "file exists") {
-				fmt.Println(redText("Error creating \"licenses\" directory: ", err, ". You will need to manually place your license file in your installation."))
+			if strings.Contains(errString, "mpm: no such file or directory") || strings.Contains(errString, "mpm.exe: no such file or directory") {
+				fmt.Println(redText("MPM was either moved, renamed, deleted, or you've lost permissions to access it."))
+			} else {
+				fmt.Println(redText("An error occurred during installation. See the error above for more information. ", err))
+			}
+
+			// Whatever made it onto disk so far still goes in the report.
+			var results []installationReportResult
+			for _, attempted := range plans[:planIndex+1] {
+				results = append(results, newInstallationReportResult(attempted, placedLicenses[attempted.Destination], verifyInstallation(attempted, expectedLicenses[attempted.Destination])))
 			}
+			saveInstallationReport(args, newReport(results, "MPM failed while installing to \""+plan.Destination+"\": "+err.Error()))
+			removeNetworkLicense()
+			fmt.Println("Press the Enter/Return key to close this program.")
+			ExitHelper()
 		}
 
-		// Copy the license file to the "licenses" directory.
-		licenseFile := filepath.Base(licensePath)
-		destPath := filepath.Join(licensesInstallationDirectory, licenseFile)
+		// Create the licenses directory and the file specified, if you specified one.
+		if licenseFileUsed && placement.Shared {
+			for _, license := range licenses {
+				destPath, backupPath, err := installLicenseFile(license, filepath.Join(plan.Destination, "licenses"), 0644)
+				if err != nil {
+					fmt.Println(redText("Error placing license file: ", err, ". You will need to manually place \""+license.Path+"\" in your installation."))
+				} else {
+					reportLicensePlacement(destPath, backupPath)
+					placedLicenses[plan.Destination] = append(placedLicenses[plan.Destination], destPath)
+				}
+			}
+		}
 
-		src, err := os.Open(licensePath)
+		// Remember this installation for list-installs.
+		err = recordInstallation(plan.Destination)
 		if err != nil {
-			fmt.Println(redText("Error opening license file: ", err, ". You will need to manually place your license file in your installation."))
+			fmt.Println(redText("Could not record this installation for future use: ", err))
 		}
-		defer src.Close()
+	}
 
-		dest, err := os.Create(destPath)
+	// Per-user licenses aren't tied to an installation, so they only need to be placed once.
+	if licenseFileUsed && !placement.Shared {
+		userLicenses, err := placeUserLicenses(licenses, placement.Users, platform, release)
+		for _, plan := range plans {
+			if !plan.Polyspace {
+				placedLicenses[plan.Destination] = append(placedLicenses[plan.Destination], userLicenses...)
+			}
+		}
 		if err != nil {
-			fmt.Println(redText("Error creating destination file: ", err, ". You will need to manually place your license file in your installation."))
+			fmt.Println(redText("Error placing license file: ", err, ". You will need to manually place your license file for the remaining users."))
+		}
+	}
+	removeNetworkLicense()
+
+	// Without a license, MATLAB will ask to be activated the first time it's opened. Get that out of the way now if you'd like.
+	// Activating means talking to MathWorks, so offline installs leave it for later.
+	if !licenseFileUsed && bundle != nil {
+		if _, keySource, _ := activationKeyFromArgs(args); keySource != "" {
+			fmt.Println(yellowText("MATLAB won't be activated since you're installing from an offline bundle. Activate it with a license file, or the next time you open MATLAB on a machine with internet access."))
+		}
+	}
+	if !licenseFileUsed && bundle == nil {
+		for _, plan := range plans {
+			if plan.Polyspace {
+				continue
+			}
+			if _, keySource, _ := activationKeyFromArgs(args); keySource != "" || promptYesNo(rl, "Would you like to activate MATLAB now? You'll need an activation key saved in a file.") {
+				err = activateInstallation(rl, args, plan.Destination)
+				if err != nil {
+					fmt.Println(redText("MATLAB could not be activated: ", err, ". You can activate it the next time you open MATLAB."))
+				} else {
+					fmt.Println(greenText("MATLAB has been activated."))
+					activatedLicense := filepath.Join(plan.Destination, "licenses", "license.lic")
+					placedLicenses[plan.Destination] = append(placedLicenses[plan.Destination], activatedLicense)
+					expectedLicenses[plan.Destination] = append(expectedLicenses[plan.Destination], activatedLicense)
+				}
+			}
+		}
+	}
+
+	// Do this after the licenses are placed so they get their own permissions.
+	if permissionsRequested {
+		fmt.Println("Applying ownership and permissions, please wait.")
+		for _, plan := range plans {
+			failures := applyPermissionPolicy(plan.Destination, placedLicenses[plan.Destination], permissions)
+			if len(failures) == 0 {
+				continue
+			}
+			fmt.Println(redText(fmt.Sprintf("%d files in \"%s\" could not be changed:", len(failures), plan.Destination)))
+			for i, failure := range failures {
+				if i == 20 {
+					fmt.Println(redText(fmt.Sprintf("- ...and %d more. See the installation report for all of them.", len(failures)-i)))
+					break
+				}
+				fmt.Println(redText("- " + failure))
+			}
+			for _, failure := range failures {
+				warnings = append(warnings, "Could not change ownership or permissions of "+failure)
+			}
+		}
+	}
+
+	// Linux doesn't give you a way to find MATLAB in your application menu on its own.
+	if platform == "linux" {
+		for _, plan := range plans {
+			if plan.Polyspace || !promptYesNo(rl, "Would you like to add MATLAB "+plan.Release+" to the application menu and use it to open .m, .mlx, and .slx files?") {
+				continue
+			}
+			created, err := createDesktopEntry(plan.Destination, plan.Release)
+			if err != nil {
+				fmt.Println(redText("Error adding MATLAB to the application menu: ", err))
+				continue
+			}
+			fmt.Println("MATLAB has been added to the application menu using \"" + strings.Join(created, "\" and \"") + "\".")
+		}
+	}
+
+	// Clusters usually hand out software through Environment Modules or Lmod instead.
+	if platform == "linux" {
+		modulefilesTrees := argumentValues(args, "--modulefiles")
+		modulefileFormats := argumentValues(args, "--modulefile-format")
+		for _, plan := range plans {
+			if plan.Polyspace {
+				continue
+			}
+			var tree, format string
+			if len(modulefilesTrees) > 0 {
+				tree = modulefilesTrees[len(modulefilesTrees)-1]
+			} else if promptYesNo(rl, "Would you like to create a modulefile for matlab/"+plan.Release+"?") {
+				tree = promptUser(rl, "Enter the path to your modulefiles tree. Press Enter to use \""+defaultModulefilesTree()+"\"")
+				if tree == "" {
+					tree = defaultModulefilesTree()
+				}
+			} else {
+				continue
+			}
+			if len(modulefileFormats) > 0 {
+				format = modulefileFormats[len(modulefileFormats)-1]
+			} else {
+				format = promptUser(rl, "Enter the modulefile format: lua (for Lmod) or tcl. Press Enter to use \"lua\"")
+				if format == "" {
+					format = "lua"
+				}
+			}
+			modulefilePath, err := writeModulefile(tree, format, plan, networkLicenseSetting(licenseServers, lic))
+			if err != nil {
+				fmt.Println(redText("Error creating modulefile: ", err))
+				continue
+			}
+			fmt.Println("A modulefile for matlab/" + plan.Release + " has been saved to \"" + modulefilePath + "\".")
 		}
-		defer dest.Close()
+	}
 
-		_, err = io.Copy(dest, src)
+	// Other programs (and other installations) can use the license server too.
+	if licenseServers != "" && promptYesNo(rl, "Would you like to set MLM_LICENSE_FILE to \""+licenseServers+"\" for future logins?") {
+		savedTo, err := writeEnvironmentSetting("MLM_LICENSE_FILE", licenseServers)
 		if err != nil {
-			fmt.Println(redText("Error copying license file: ", err, ". You will need to manually place your license file in your installation."))
+			fmt.Println(redText("Error setting MLM_LICENSE_FILE: ", err))
+		} else {
+			fmt.Println("MLM_LICENSE_FILE has been saved to " + savedTo + ". It will take effect the next time you log in.")
 		}
 	}
 
+	// Don't just take MPM's word for it.
+	allPassed := true
+	var results []installationReportResult
+	for _, plan := range plans {
+		checks := verifyInstallation(plan, expectedLicenses[plan.Destination])
+		if !printVerification(plan.Destination, checks) {
+			allPassed = false
+		}
+		results = append(results, newInstallationReportResult(plan, placedLicenses[plan.Destination], checks))
+	}
+
+	saveInstallationReport(args, newReport(results, ""))
+	if !allPassed {
+		fmt.Println(redText("Installation finished, but some checks failed. See the checklist above for more information. Press the Enter/Return key to close this program."))
+		ExitHelper()
+	}
+
 	fmt.Println(greenText("Installation finished! Press the Enter/Return key to close this program."))
 	ExitHelper()
 }
 
+// Ask where products should be installed and create the directory if needed.
+func promptInstallationPath(rl *readline.Instance, question string, defaultPath string) string {
+	redText := color.New(color.FgRed).SprintFunc()
+	for {
+		fmt.Print(question+" "+
+			"Press Enter to install to default path: \"", defaultPath, "\"\n> ")
+
+		installPath, err := readUserInput(rl)
+		if err != nil {
+			if err.Error() == "Interrupt" {
+				fmt.Println(redText("Exiting from user input."))
+				os.Exit(0)
+			} else {
+				fmt.Println(redText("Error reading line: ", err))
+				continue
+			}
+		}
+
+		installPath = strings.TrimSpace(installPath)
+
+		if installPath == "" {
+			installPath = defaultPath
+		} else {
+			if _, err := os.Stat(installPath); os.IsNotExist(err) {
+
+				// If the folder does not exist, try to create it.
+				if _, err := os.Stat(installPath); os.IsNotExist(err) {
+					if err := os.MkdirAll(installPath, 0755); err != nil {
+						fmt.Println(redText("Error creating directory: ", err, " Please pick a different installation path."))
+						continue
+					} else {
+						fullPath, err := filepath.Abs(installPath)
+						if err != nil {
+							fmt.Println(redText("Error reading newly-created directory's full path: ", err, " Please pick a different installation path."))
+							continue
+						} else {
+							fmt.Println("Directory successfully created:", fullPath)
+						}
+					}
+				}
+			} else if err != nil {
+				fullPath, _ := filepath.Abs(installPath)
+				fmt.Println(redText("Error selecting directory: ", fullPath, " Please pick a different installation path."))
+				continue
+			}
+		}
+		return installPath
+	}
+}
+
 func hasAdminRights() (bool, error) {
 
 	// Find out where Windows is installed.
@@ -718,6 +1095,11 @@ func downloadFile(url string, filePath string) error {
 	}
 	defer response.Body.Close()
 
+	// Don't save an error page as if it were MPM.
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("the server responded with %s", response.Status)
+	}
+
 	file, err := os.Create(filePath)
 	if err != nil {
 		return err
@@ -768,6 +1150,53 @@ func readUserInput(rl *readline.Instance) (string, error) {
 	return line, nil
 }
 
+// Ask the user something and keep asking until we get an answer. Interrupting closes the program, just like everywhere else.
+func promptUser(rl *readline.Instance, question string) string {
+	redText := color.New(color.FgRed).SprintFunc()
+	for {
+		fmt.Print(question + "\n> ")
+		answer, err := readUserInput(rl)
+		if err != nil {
+			if err.Error() == "Interrupt" {
+				fmt.Println(redText("Exiting from user input."))
+				os.Exit(0)
+			}
+			fmt.Println(redText("Error reading line: ", err))
+			continue
+		}
+		return strings.TrimSpace(answer)
+	}
+}
+
+// Same as promptUser, but only accepts yes or no.
+func promptYesNo(rl *readline.Instance, question string) bool {
+	redText := color.New(color.FgRed).SprintFunc()
+	for {
+		answer := strings.ToLower(promptUser(rl, question+" (y/n)"))
+		switch answer {
+		case "y", "yes", "t", "true":
+			return true
+		case "n", "no", "f", "false":
+			return false
+		}
+		fmt.Println(redText("Invalid choice. Please enter either 'y' or 'n'."))
+	}
+}
+
+// Collect the values given to an argument such as "--root /opt/MATLAB" or "--root=/opt/MATLAB". Arguments can be repeated.
+func argumentValues(args []string, name string) []string {
+	var values []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == name && i+1 < len(args) {
+			values = append(values, args[i+1])
+			i++
+		} else if strings.HasPrefix(args[i], name+"=") {
+			values = append(values, strings.TrimPrefix(args[i], name+"="))
+		}
+	}
+	return values
+}
+
 // List and auto-complete files and folders with tabbing.
 func listFiles(line string) []string {
 	dir, file := filepath.Split(line)
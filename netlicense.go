@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// FlexNet's default port when none is given.
+const defaultLicenseServerPort = 27000
+
+// A license server entered as port@host.
+type licenseServerAddress struct {
+	Host string
+	Port int
+}
+
+func (address licenseServerAddress) String() string {
+	return strconv.Itoa(address.Port) + "@" + address.Host
+}
+
+// Check if the license prompt was given license servers instead of a file path.
+func looksLikeLicenseServer(input string) bool {
+	if !strings.Contains(input, "@") || strings.ContainsAny(input, `/\`) {
+		return false
+	}
+	_, err := os.Stat(input)
+	return os.IsNotExist(err)
+}
+
+// Read license servers in the form port@host. Several can be given, separated by commas, semicolons, or spaces.
+// The port can be left out to use 27000.
+func parseLicenseServers(input string) ([]licenseServerAddress, error) {
+	var servers []licenseServerAddress
+	entries := strings.FieldsFunc(input, func(character rune) bool {
+		return character == ',' || character == ';' || character == ' ' || character == '\t'
+	})
+	for _, entry := range entries {
+		portText, host, found := strings.Cut(entry, "@")
+		if !found {
+			host = entry
+			portText = ""
+		}
+		host = strings.TrimSpace(host)
+		if host == "" || strings.ContainsAny(host, " @\"'") {
+			return nil, fmt.Errorf("\"%s\" is not a valid license server. Use the form port@host, such as 27000@licenseserver.example.com", entry)
+		}
+
+		port := defaultLicenseServerPort
+		if portText != "" {
+			var err error
+			port, err = strconv.Atoi(portText)
+			if err != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("\"%s\" is not a valid port number", portText)
+			}
+		}
+		servers = append(servers, licenseServerAddress{Host: host, Port: port})
+	}
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no license servers were given")
+	}
+	if len(servers) != 1 && len(servers) != 3 {
+		return nil, fmt.Errorf("enter either one license server or three redundant license servers, not %d", len(servers))
+	}
+	return servers, nil
+}
+
+// The contents of a network.lic for clients. The host ID is never checked on clients, so ANY is used.
+func networkLicenseContents(servers []licenseServerAddress) string {
+	var contents strings.Builder
+	contents.WriteString("# Network license created by MPM.Go.\n")
+	for _, server := range servers {
+		contents.WriteString("SERVER " + server.Host + " ANY " + strconv.Itoa(server.Port) + "\n")
+	}
+	contents.WriteString("USE_SERVER\n")
+	return contents.String()
+}
+
+// Write network.lic somewhere temporary so it can be checked and copied into the installation like any other license file.
+// Call the returned function to delete it once it's been copied.
+func createNetworkLicense(servers []licenseServerAddress) (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "mpm-license")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+	licensePath := filepath.Join(tempDir, "network.lic")
+	err = os.WriteFile(licensePath, []byte(networkLicenseContents(servers)), 0644)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return licensePath, cleanup, nil
+}
+
+// The value MLM_LICENSE_FILE should be set to. Redundant servers are separated by commas.
+func licenseServerSetting(servers []licenseServerAddress) string {
+	addresses := make([]string, len(servers))
+	for i, server := range servers {
+		addresses[i] = server.String()
+	}
+	return strings.Join(addresses, ",")
+}
+
+// Set an environment variable for future logins. On Windows, this is a system-wide setting.
+// Elsewhere, it goes in /etc/profile.d when we have the rights to, and in your ~/.profile otherwise.
+// Returns where the setting was saved.
+func writeEnvironmentSetting(name string, value string) (string, error) {
+	if runtime.GOOS == "windows" {
+		output, err := exec.Command("setx", name, value, "/M").CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+		}
+		return "the system environment variables", nil
+	}
+
+	line := "export " + name + "=\"" + value + "\""
+	snippetName := "mpm-" + strings.ToLower(strings.ReplaceAll(name, "_", "-")) + ".sh"
+
+	if os.Geteuid() == 0 {
+		snippetPath := filepath.Join("/etc/profile.d", snippetName)
+		err := os.WriteFile(snippetPath, []byte("# Created by MPM.Go.\n"+line+"\n"), 0644)
+		if err == nil {
+			return snippetPath, nil
+		}
+	}
+
+	// Per-user fallback. Replace our old line if there is one so we don't pile up duplicates.
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	profilePath := filepath.Join(homeDir, ".profile")
+	marker := "# Added by MPM.Go (" + name + ")"
+
+	existing, err := os.ReadFile(profilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	var kept []string
+	skipNext := false
+	for _, existingLine := range strings.Split(strings.TrimRight(string(existing), "\n"), "\n") {
+		if skipNext {
+			skipNext = false
+			continue
+		}
+		if existingLine == marker {
+			skipNext = true
+			continue
+		}
+		kept = append(kept, existingLine)
+	}
+	if len(kept) == 1 && kept[0] == "" {
+		kept = nil
+	}
+	kept = append(kept, marker, line)
+
+	err = os.WriteFile(profilePath, []byte(strings.Join(kept, "\n")+"\n"), 0644)
+	if err != nil {
+		return "", err
+	}
+	return profilePath, nil
+}
+
+// How long to wait on each license server port before giving up.
+const licenseServerTimeout = 5 * time.Second
+
+// Try to reach every license server (and vendor daemon port, if one is set) in a license file and explain anything that fails.
+// Returns what couldn't be reached and why, which is empty if everything could be.
+func unreachableLicenseServers(lic licenseFile) []string {
+	greenText := color.New(color.FgHiGreen).SprintFunc()
+	redText := color.New(color.FgRed).SprintFunc()
+
+	fmt.Println("Checking that your license server can be reached, please wait.")
+	var unreachable []string
+	for _, server := range lic.Servers {
+
+		// Without a port, the license manager uses the first free one from 27000-27009.
+		ports := []int{server.Port}
+		if server.Port == 0 {
+			ports = nil
+			for port := defaultLicenseServerPort; port <= defaultLicenseServerPort+9; port++ {
+				ports = append(ports, port)
+			}
+		}
+		err := probeLicenseServer(server.Host, ports, licenseServerTimeout)
+		if err != nil {
+			problem := "License manager on " + server.Host + ": " + explainProbeError(server.Host, err)
+			unreachable = append(unreachable, problem)
+			fmt.Println(redText("- " + problem))
+		} else {
+			fmt.Println(greenText("- License manager on " + server.Host + ": reachable"))
+		}
+
+		// MathWorks' vendor daemon gets a random port unless the license file pins one, in which case the firewall needs to allow it too.
+		for _, daemon := range lic.Daemons {
+			if daemon.Port == 0 {
+				continue
+			}
+			err := probeLicenseServer(server.Host, []int{daemon.Port}, licenseServerTimeout)
+			label := daemon.Name + " vendor daemon on " + server.Host + " (port " + strconv.Itoa(daemon.Port) + ")"
+			if err != nil {
+				problem := label + ": " + explainProbeError(server.Host, err)
+				unreachable = append(unreachable, problem)
+				fmt.Println(redText("- " + problem))
+			} else {
+				fmt.Println(greenText("- " + label + ": reachable"))
+			}
+		}
+	}
+	return unreachable
+}
+
+// Look up a host and try each port until one accepts a connection. The last error is returned if none do.
+func probeLicenseServer(host string, ports []int, timeout time.Duration) error {
+	if _, err := net.LookupHost(host); err != nil {
+		return err
+	}
+	var lastErr error
+	for _, port := range ports {
+		connection, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+		if err == nil {
+			connection.Close()
+			return nil
+		}
+		lastErr = err
+
+		// If one port times out, the rest will too. No need to make you wait for all of them.
+		var netError net.Error
+		if errors.As(err, &netError) && netError.Timeout() {
+			break
+		}
+	}
+	return lastErr
+}
+
+// Turn a connection error into something that tells you what to do about it.
+func explainProbeError(host string, err error) string {
+	var dnsError *net.DNSError
+	var netError net.Error
+	switch {
+	case errors.As(err, &dnsError):
+		return "the hostname \"" + host + "\" could not be found. Check its spelling, or use the server's full name or IP address."
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "the connection was refused. The server is up, but the license manager isn't running or is using a different port. Ask your license administrator to check lmgrd."
+	case errors.As(err, &netError) && netError.Timeout():
+		return "the connection timed out. The server may be down, or a firewall is blocking the port. Ask your network administrator to allow it."
+	}
+	return err.Error()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// A stand-in license server on a free local port. Returns the port.
+func listenLikeLicenseServer(t *testing.T) (net.Listener, int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			connection.Close()
+		}
+	}()
+	return listener, listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbeLicenseServer(t *testing.T) {
+	listener, port := listenLikeLicenseServer(t)
+	defer listener.Close()
+
+	if err := probeLicenseServer("127.0.0.1", []int{port}, time.Second); err != nil {
+		t.Errorf("probing a listening port failed: %v", err)
+	}
+
+	// Only one of the ports needs to answer, like lmgrd picking one from 27000-27009.
+	closedListener, closedPort := listenLikeLicenseServer(t)
+	closedListener.Close()
+	if err := probeLicenseServer("127.0.0.1", []int{closedPort, port}, time.Second); err != nil {
+		t.Errorf("probing a closed port and then a listening one failed: %v", err)
+	}
+}
+
+func TestProbeLicenseServerRefused(t *testing.T) {
+	listener, port := listenLikeLicenseServer(t)
+	listener.Close()
+
+	err := probeLicenseServer("127.0.0.1", []int{port}, time.Second)
+	if err == nil {
+		t.Fatal("probing a closed port succeeded")
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("got %v, want connection refused", err)
+	}
+	if explanation := explainProbeError("127.0.0.1", err); !strings.Contains(explanation, "refused") {
+		t.Errorf("explanation = %q", explanation)
+	}
+}
+
+func TestParseLicenseServers(t *testing.T) {
+	servers, err := parseLicenseServers("27000@one, 27001@two;@three")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 3 || servers[0].String() != "27000@one" || servers[1].String() != "27001@two" || servers[2].Host != "three" {
+		t.Errorf("servers = %+v", servers)
+	}
+	if _, err := parseLicenseServers("notaport@host"); err == nil {
+		t.Error("an invalid port was accepted")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Who should own a shared installation and how open it should be. UID and GID are -1 when they should be left alone.
+// Mode is either empty (leave permissions alone), "group-writable", or "read-only".
+type permissionPolicy struct {
+	UID  int
+	GID  int
+	Mode string
+}
+
+// Read --owner, --group, and --mode-policy. Returns false if none of them were given.
+func parsePermissionPolicy(args []string) (permissionPolicy, bool, error) {
+	policy := permissionPolicy{UID: -1, GID: -1}
+	owners := argumentValues(args, "--owner")
+	groups := argumentValues(args, "--group")
+	modes := argumentValues(args, "--mode-policy")
+	if len(owners) == 0 && len(groups) == 0 && len(modes) == 0 {
+		return policy, false, nil
+	}
+	if runtime.GOOS == "windows" {
+		return policy, true, fmt.Errorf("--owner, --group, and --mode-policy aren't available on Windows")
+	}
+
+	if len(owners) > 0 {
+		owner := owners[len(owners)-1]
+		account, err := user.Lookup(owner)
+		if err != nil {
+			account, err = user.LookupId(owner)
+		}
+		if err != nil {
+			return policy, true, fmt.Errorf("the user \"%s\" could not be found", owner)
+		}
+		policy.UID, _ = strconv.Atoi(account.Uid)
+	}
+	if len(groups) > 0 {
+		groupName := groups[len(groups)-1]
+		group, err := user.LookupGroup(groupName)
+		if err != nil {
+			group, err = user.LookupGroupId(groupName)
+		}
+		if err != nil {
+			return policy, true, fmt.Errorf("the group \"%s\" could not be found", groupName)
+		}
+		policy.GID, _ = strconv.Atoi(group.Gid)
+	}
+	if (policy.UID != -1 || policy.GID != -1) && os.Geteuid() != 0 {
+		return policy, true, fmt.Errorf("you need to run this program as root to use --owner or --group")
+	}
+
+	if len(modes) > 0 {
+		policy.Mode = strings.ToLower(modes[len(modes)-1])
+		if policy.Mode != "group-writable" && policy.Mode != "read-only" {
+			return policy, true, fmt.Errorf("unknown mode policy \"%s\". Use group-writable or read-only", policy.Mode)
+		}
+	}
+	return policy, true, nil
+}
+
+// The permissions something should have under this policy. Executables stay executable.
+// License files are never writable by anyone but their owner, and not even by them under read-only.
+func (policy permissionPolicy) modeFor(info fs.FileInfo, isLicense bool) os.FileMode {
+	current := info.Mode().Perm()
+	executable := current&0111 != 0
+	switch {
+	case policy.Mode == "":
+		return current
+	case isLicense && policy.Mode == "read-only":
+		return 0444
+	case isLicense:
+		return 0644
+	case info.IsDir() && policy.Mode == "group-writable":
+		return 0775 | os.ModeSetgid // New files inherit the group.
+	case info.IsDir():
+		return 0755
+	case policy.Mode == "group-writable" && executable:
+		return 0775
+	case policy.Mode == "group-writable":
+		return 0664
+	case executable:
+		return 0755
+	}
+	return 0644
+}
+
+// Apply a policy to everything in an installation. Symlinks only get their ownership changed, since changing their permissions
+// would change whatever they point to.
+// Returns what couldn't be changed.
+func applyPermissionPolicy(installPath string, licensePaths []string, policy permissionPolicy) []string {
+	isLicense := make(map[string]bool)
+	for _, licensePath := range licensePaths {
+		isLicense[filepath.Clean(licensePath)] = true
+	}
+
+	var failures []string
+	filepath.WalkDir(installPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			failures = append(failures, path+": "+err.Error())
+			return nil
+		}
+		if policy.UID != -1 || policy.GID != -1 {
+			if err := os.Lchown(path, policy.UID, policy.GID); err != nil {
+				failures = append(failures, path+": "+err.Error())
+			}
+		}
+		if policy.Mode != "" && entry.Type()&fs.ModeSymlink == 0 {
+			info, err := entry.Info()
+			if err != nil {
+				failures = append(failures, path+": "+err.Error())
+				return nil
+			}
+			if err := os.Chmod(path, policy.modeFor(info, isLicense[filepath.Clean(path)])); err != nil {
+				failures = append(failures, path+": "+err.Error())
+			}
+		}
+		return nil
+	})
+	return failures
+}
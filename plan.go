@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Everything needed for one run of "mpm install". MATLAB and Polyspace products each get their own so they never share a destination.
+type installPlan struct {
+	Release     string
+	Destination string
+	Products    []string
+	Polyspace   bool
+	Source      string // Installation files downloaded ahead of time, if any.
+}
+
+// The command used to launch MPM for this plan.
+func (plan installPlan) mpmArgs(mpmFullPath string) []string {
+	cmdArgs := []string{
+		mpmFullPath,
+		"install",
+		"--release=" + plan.Release,
+		"--destination=" + plan.Destination,
+	}
+	if plan.Source != "" {
+		cmdArgs = append(cmdArgs, "--source="+plan.Source)
+	}
+	cmdArgs = append(cmdArgs, "--products")
+	return append(cmdArgs, plan.Products...)
+}
+
+// A readable name for messages, such as "MATLAB" or "Polyspace".
+func (plan installPlan) family() string {
+	if plan.Polyspace {
+		return "Polyspace"
+	}
+	return "MATLAB"
+}
+
+// All Polyspace products are named Polyspace_Something, such as Polyspace_Bug_Finder and Polyspace_Code_Prover_Server.
+func isPolyspaceProduct(product string) bool {
+	return strings.HasPrefix(product, "Polyspace_")
+}
+
+// Separate Polyspace products from everything else, keeping the order they were given in.
+func splitPolyspaceProducts(products []string) (matlabProducts []string, polyspaceProducts []string) {
+	for _, product := range products {
+		if isPolyspaceProduct(product) {
+			polyspaceProducts = append(polyspaceProducts, product)
+		} else {
+			matlabProducts = append(matlabProducts, product)
+		}
+	}
+	return matlabProducts, polyspaceProducts
+}
+
+// The default installation path based on your OS. Polyspace gets its own folder so it isn't mixed in with MATLAB.
+func defaultInstallationPath(platform string, release string, polyspace bool) string {
+	family := "MATLAB"
+	if polyspace {
+		family = "Polyspace"
+	}
+	switch platform {
+	case "macOSx64", "macOSARM":
+		return "/Applications/" + family + "_" + release + ".app"
+	case "windows":
+		return "C:\\Program Files\\" + family + "\\" + release
+	case "linux":
+		return "/usr/local/" + family + "/" + release
+	}
+	return ""
+}
+
+// Check if a path is, or is inside of, a MATLAB installation. matlabDestination is where MATLAB is about to be installed, if anywhere.
+func insideMATLABRoot(path string, matlabDestination string) bool {
+	fullPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	if matlabDestination != "" {
+		matlabPath, err := filepath.Abs(matlabDestination)
+		if err == nil && (fullPath == matlabPath || strings.HasPrefix(fullPath, matlabPath+string(filepath.Separator))) {
+			return true
+		}
+	}
+	for {
+		if isMATLABRoot(fullPath) {
+			return true
+		}
+		parent := filepath.Dir(fullPath)
+		if parent == fullPath {
+			return false
+		}
+		fullPath = parent
+	}
+}
+
+// Check if a directory already holds a MATLAB installation.
+func isMATLABRoot(path string) bool {
+	for _, launcher := range []string{"matlab", "matlab.exe"} {
+		if _, err := os.Stat(filepath.Join(path, "bin", launcher)); err == nil {
+			return true
+		}
+	}
+	found, err := readInstallation(path)
+	if err != nil {
+		return false
+	}
+	for _, product := range found.Products {
+		if product == "MATLAB" {
+			return true
+		}
+	}
+	return false
+}
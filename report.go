@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Everything about one run of the wizard, for anyone who needs a record of what was installed.
+type installationReport struct {
+	Host             string                     `json:"host"`
+	OS               string                     `json:"os"`
+	WrapperVersion   string                     `json:"wrapperVersion"`
+	MPMPath          string                     `json:"mpmPath"`
+	MPMSHA256        string                     `json:"mpmSha256"`
+	Release          string                     `json:"release"`
+	Started          time.Time                  `json:"started"`
+	Finished         time.Time                  `json:"finished"`
+	DurationSeconds  int64                      `json:"durationSeconds"`
+	Installations    []installationReportResult `json:"installations"`
+	LicensePlacement string                     `json:"licensePlacement"`
+	Warnings         []string                   `json:"warnings"`
+	Error            string                     `json:"error,omitempty"` // What stopped the installation, if anything did.
+}
+
+// What happened to one destination.
+type installationReportResult struct {
+	Family            string              `json:"family"`
+	Destination       string              `json:"destination"`
+	ProductsRequested []string            `json:"productsRequested"`
+	ProductsInstalled []string            `json:"productsInstalled"`
+	DiskUsedBytes     int64               `json:"diskUsedBytes"`
+	Licenses          []string            `json:"licenses"`
+	Checks            []verificationCheck `json:"checks"`
+}
+
+// Fill in the parts of a report that need to be looked up after installing.
+func newInstallationReportResult(plan installPlan, licensePaths []string, checks []verificationCheck) installationReportResult {
+	result := installationReportResult{
+		Family:            plan.family(),
+		Destination:       plan.Destination,
+		ProductsRequested: plan.Products,
+		Licenses:          licensePaths,
+		Checks:            checks,
+	}
+	if found, err := readInstallation(plan.Destination); err == nil {
+		result.ProductsInstalled = found.Products
+	}
+	if size, err := directorySize(plan.Destination); err == nil {
+		result.DiskUsedBytes = size
+	}
+	return result
+}
+
+// How the license was placed, in words.
+func describeLicensePlacement(licenseFileUsed bool, placement licensePlacement) string {
+	switch {
+	case !licenseFileUsed:
+		return "No license file was provided."
+	case placement.Shared:
+		return "Shared with everyone using the installation."
+	}
+	return "Placed for the user(s) " + strings.Join(placement.Users, ", ") + "."
+}
+
+// Write the report in the format and folder given with --report-format and --report-dir and say where it went.
+func saveInstallationReport(args []string, report installationReport) {
+	redText := color.New(color.FgRed).SprintFunc()
+
+	reportFormat, reportDirectory := "markdown", ""
+	if formats := argumentValues(args, "--report-format"); len(formats) > 0 {
+		reportFormat = formats[len(formats)-1]
+	}
+	if directories := argumentValues(args, "--report-dir"); len(directories) > 0 {
+		reportDirectory = directories[len(directories)-1]
+	}
+	reportPath, err := writeInstallationReport(report, reportFormat, reportDirectory)
+	if err != nil {
+		fmt.Println(redText("Error writing installation report: ", err))
+	} else {
+		fmt.Println("An installation report has been saved to \"" + reportPath + "\".")
+	}
+}
+
+// Write the report in Markdown, HTML, or JSON. It goes in reportDirectory if one was given, and next to the first installation otherwise.
+// Returns where it was saved.
+func writeInstallationReport(report installationReport, format string, reportDirectory string) (string, error) {
+	if reportDirectory == "" {
+		if len(report.Installations) == 0 {
+			return "", fmt.Errorf("nothing was installed")
+		}
+		reportDirectory = filepath.Dir(report.Installations[0].Destination)
+	}
+	if err := os.MkdirAll(reportDirectory, 0755); err != nil {
+		return "", err
+	}
+
+	var contents []byte
+	var extension string
+	switch strings.ToLower(format) {
+	case "", "markdown", "md":
+		contents = []byte(markdownReport(report))
+		extension = ".md"
+	case "html":
+		var builder strings.Builder
+		if err := htmlReportTemplate.Execute(&builder, report); err != nil {
+			return "", err
+		}
+		contents = []byte(builder.String())
+		extension = ".html"
+	case "json":
+		var err error
+		contents, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		extension = ".json"
+	default:
+		return "", fmt.Errorf("unknown report format \"%s\". Use markdown, html, or json", format)
+	}
+
+	reportPath := filepath.Join(reportDirectory, "MPM_report_"+report.Release+"_"+report.Finished.Format("20060102-150405")+extension)
+	if err := os.WriteFile(reportPath, contents, 0644); err != nil {
+		return "", err
+	}
+	return reportPath, nil
+}
+
+func markdownReport(report installationReport) string {
+	var builder strings.Builder
+	line := func(format string, values ...any) {
+		builder.WriteString(fmt.Sprintf(format, values...) + "\n")
+	}
+
+	line("# %s installation report", report.Release)
+	line("")
+	line("| | |")
+	line("|---|---|")
+	line("| Host | %s |", report.Host)
+	line("| OS | %s |", report.OS)
+	line("| MPM.Go version | %s |", report.WrapperVersion)
+	line("| MPM | `%s` |", report.MPMPath)
+	line("| MPM SHA-256 | `%s` |", report.MPMSHA256)
+	line("| Started | %s |", report.Started.Format(time.RFC1123))
+	line("| Finished | %s |", report.Finished.Format(time.RFC1123))
+	line("| Duration | %s |", time.Duration(report.DurationSeconds)*time.Second)
+	line("| License placement | %s |", report.LicensePlacement)
+	if report.Error != "" {
+		line("| Error | %s |", report.Error)
+	}
+
+	for _, result := range report.Installations {
+		line("")
+		line("## %s in `%s`", result.Family, result.Destination)
+		line("")
+		line("Disk used: %s", formatBytes(result.DiskUsedBytes))
+		line("")
+		line("Products requested: %s", strings.Join(result.ProductsRequested, ", "))
+		line("")
+		line("Products installed: %s", strings.Join(result.ProductsInstalled, ", "))
+		if len(result.Licenses) > 0 {
+			line("")
+			line("License files:")
+			line("")
+			for _, licensePath := range result.Licenses {
+				line("- `%s`", licensePath)
+			}
+		}
+		line("")
+		line("Checks:")
+		line("")
+		for _, check := range result.Checks {
+			if check.Passed {
+				line("- [x] %s", check.Description)
+			} else {
+				line("- [ ] %s: %s", check.Description, check.Detail)
+			}
+		}
+	}
+
+	if len(report.Warnings) > 0 {
+		line("")
+		line("## Warnings")
+		line("")
+		for _, warning := range report.Warnings {
+			line("- %s", warning)
+		}
+	}
+	return builder.String()
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"formatBytes": formatBytes,
+	"join":        strings.Join,
+	"rfc1123":     func(t time.Time) string { return t.Format(time.RFC1123) },
+	"seconds":     func(seconds int64) string { return (time.Duration(seconds) * time.Second).String() },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Release}} installation report</title>
+</head>
+<body>
+<h1>{{.Release}} installation report</h1>
+<table>
+<tr><th>Host</th><td>{{.Host}}</td></tr>
+<tr><th>OS</th><td>{{.OS}}</td></tr>
+<tr><th>MPM.Go version</th><td>{{.WrapperVersion}}</td></tr>
+<tr><th>MPM</th><td><code>{{.MPMPath}}</code></td></tr>
+<tr><th>MPM SHA-256</th><td><code>{{.MPMSHA256}}</code></td></tr>
+<tr><th>Started</th><td>{{rfc1123 .Started}}</td></tr>
+<tr><th>Finished</th><td>{{rfc1123 .Finished}}</td></tr>
+<tr><th>Duration</th><td>{{seconds .DurationSeconds}}</td></tr>
+<tr><th>License placement</th><td>{{.LicensePlacement}}</td></tr>
+{{if .Error}}<tr><th>Error</th><td>{{.Error}}</td></tr>
+{{end}}</table>
+{{range .Installations}}
+<h2>{{.Family}} in <code>{{.Destination}}</code></h2>
+<p>Disk used: {{formatBytes .DiskUsedBytes}}</p>
+<p>Products requested: {{join .ProductsRequested ", "}}</p>
+<p>Products installed: {{join .ProductsInstalled ", "}}</p>
+{{if .Licenses}}<p>License files:</p>
+<ul>
+{{range .Licenses}}<li><code>{{.}}</code></li>
+{{end}}</ul>{{end}}
+<p>Checks:</p>
+<ul>
+{{range .Checks}}<li>{{if .Passed}}PASS{{else}}FAIL{{end}}: {{.Description}}{{if not .Passed}}: {{.Detail}}{{end}}</li>
+{{end}}</ul>
+{{end}}
+{{if .Warnings}}<h2>Warnings</h2>
+<ul>
+{{range .Warnings}}<li>{{.}}</li>
+{{end}}</ul>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	readline "github.com/Jestzer/readlineJestzer"
+)
+
+// The kinds of scripts the export command can write. These replay a session on machines where this program can't run.
+var scriptFormats = []string{"shell", "powershell", "ansible"}
+
+func isScriptFormat(format string) bool {
+	for _, scriptFormat := range scriptFormats {
+		if format == scriptFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// The platform an exported script is for. PowerShell scripts are for Windows, and everything else is for the machine you're on,
+// unless that's Windows, in which case it's for Linux.
+func exportTargetPlatform(format string, platform string) string {
+	switch {
+	case format == "powershell":
+		return "windows"
+	case platform == "windows":
+		return "linux"
+	}
+	return platform
+}
+
+// Where exported scripts download MPM to if you don't pick somewhere.
+func exportDefaultDownloadPath(targetPlatform string) string {
+	if targetPlatform == "windows" {
+		return `C:\Windows\Temp`
+	}
+	return "/tmp"
+}
+
+// Join paths for the platform a script is for rather than the one we're on.
+func joinTargetPath(targetPlatform string, elements ...string) string {
+	separator := "/"
+	if targetPlatform == "windows" {
+		separator = `\`
+	}
+	path := strings.TrimRight(elements[0], `/\`)
+	for _, element := range elements[1:] {
+		path += separator + element
+	}
+	return path
+}
+
+// Ask for a destination without creating anything, since it's on another machine.
+func promptExportDestination(rl *readline.Instance, question string, defaultPath string) string {
+	answer := promptUser(rl, question+" Press Enter to use \""+defaultPath+"\"")
+	if answer == "" {
+		return defaultPath
+	}
+	return answer
+}
+
+// Everything an exported script replays: the same plans the wizard would run, plus where MPM goes and which licenses get copied.
+// License files are embedded in the script since they won't be on the machine it runs on.
+type sessionScript struct {
+	Platform        string
+	MPMDownloadPath string
+	Plans           []installPlan
+	Licenses        []licenseSource
+	LicenseContents []string
+}
+
+func (script sessionScript) mpmPath() string {
+	return joinTargetPath(script.Platform, script.MPMDownloadPath, mpmExecutableName(script.Platform))
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func powerShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// YAML understands JSON strings, which saves us from YAML's many quoting rules.
+func yamlQuote(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
+
+// A heredoc delimiter that doesn't show up in the text it ends.
+func heredocDelimiter(contents string) string {
+	delimiter := "MPM_LICENSE"
+	for strings.Contains(contents, delimiter) {
+		delimiter += "_END"
+	}
+	return delimiter
+}
+
+func (script sessionScript) shell() string {
+	var builder strings.Builder
+	builder.WriteString("#!/bin/sh\n# Created by MPM.Go " + versionNumber + ". Run this as root to install the same products the same way.\nset -e\n\n")
+	builder.WriteString("mkdir -p " + shellQuote(script.MPMDownloadPath) + "\n")
+	builder.WriteString("curl -fL -o " + shellQuote(script.mpmPath()) + " " + shellQuote(mpmURLFor(script.Platform)) + "\n")
+	builder.WriteString("chmod +x " + shellQuote(script.mpmPath()) + "\n")
+	for _, plan := range script.Plans {
+		var quoted []string
+		for _, arg := range plan.mpmArgs(script.mpmPath()) {
+			quoted = append(quoted, shellQuote(arg))
+		}
+		builder.WriteString("\n# " + plan.family() + "\n" + strings.Join(quoted, " ") + "\n")
+		if len(script.Licenses) == 0 {
+			continue
+		}
+		licensesDirectory := joinTargetPath(script.Platform, plan.Destination, "licenses")
+		builder.WriteString("mkdir -p " + shellQuote(licensesDirectory) + "\n")
+		for i, license := range script.Licenses {
+			contents := script.LicenseContents[i]
+			if !strings.HasSuffix(contents, "\n") {
+				contents += "\n"
+			}
+			delimiter := heredocDelimiter(contents)
+			builder.WriteString("cat > " + shellQuote(joinTargetPath(script.Platform, licensesDirectory, license.Name)) + " <<'" + delimiter + "'\n" + contents + delimiter + "\n")
+		}
+	}
+	return builder.String()
+}
+
+func (script sessionScript) powerShell() string {
+	var builder strings.Builder
+	builder.WriteString("# Created by MPM.Go " + versionNumber + ". Run this as an administrator to install the same products the same way.\n$ErrorActionPreference = 'Stop'\n\n")
+	builder.WriteString("New-Item -ItemType Directory -Force -Path " + powerShellQuote(script.MPMDownloadPath) + " | Out-Null\n")
+	builder.WriteString("Invoke-WebRequest -Uri " + powerShellQuote(mpmURLFor(script.Platform)) + " -OutFile " + powerShellQuote(script.mpmPath()) + "\n")
+	for _, plan := range script.Plans {
+		cmdArgs := plan.mpmArgs(script.mpmPath())
+		var quoted []string
+		for _, arg := range cmdArgs {
+			quoted = append(quoted, powerShellQuote(arg))
+		}
+		builder.WriteString("\n# " + plan.family() + "\n& " + strings.Join(quoted, " ") + "\n")
+		builder.WriteString("if ($LASTEXITCODE -ne 0) { throw \"MPM failed with exit code $LASTEXITCODE.\" }\n")
+		if len(script.Licenses) == 0 {
+			continue
+		}
+		licensesDirectory := joinTargetPath(script.Platform, plan.Destination, "licenses")
+		builder.WriteString("New-Item -ItemType Directory -Force -Path " + powerShellQuote(licensesDirectory) + " | Out-Null\n")
+		for i, license := range script.Licenses {
+
+			// Nothing can end a single-quoted here-string early except a line starting with '@.
+			contents := strings.ReplaceAll(strings.TrimRight(script.LicenseContents[i], "\r\n"), "\n'@", "\n '@")
+			builder.WriteString("@'\n" + contents + "\n'@ | Set-Content -Encoding ASCII -Path " + powerShellQuote(joinTargetPath(script.Platform, licensesDirectory, license.Name)) + "\n")
+		}
+	}
+	return builder.String()
+}
+
+func (script sessionScript) ansible() string {
+	var builder strings.Builder
+	builder.WriteString("# Created by MPM.Go " + versionNumber + ". Include these tasks in a play that runs with become: true.\n")
+	builder.WriteString("- name: Create MPM's download directory\n  ansible.builtin.file:\n    path: " + yamlQuote(script.MPMDownloadPath) + "\n    state: directory\n\n")
+	builder.WriteString("- name: Download MPM\n  ansible.builtin.get_url:\n    url: " + yamlQuote(mpmURLFor(script.Platform)) + "\n    dest: " + yamlQuote(script.mpmPath()) + "\n    mode: \"0755\"\n    force: true\n")
+	for _, plan := range script.Plans {
+		cmdArgs := plan.mpmArgs(script.mpmPath())
+		builder.WriteString("\n- name: Install " + plan.family() + " " + plan.Release + "\n  ansible.builtin.command:\n    argv:\n")
+		for _, arg := range cmdArgs {
+			builder.WriteString("      - " + yamlQuote(arg) + "\n")
+		}
+		if len(script.Licenses) == 0 {
+			continue
+		}
+		licensesDirectory := joinTargetPath(script.Platform, plan.Destination, "licenses")
+		builder.WriteString("\n- name: Create " + plan.family() + "'s licenses directory\n  ansible.builtin.file:\n    path: " + yamlQuote(licensesDirectory) + "\n    state: directory\n    mode: \"0755\"\n")
+		for i, license := range script.Licenses {
+			builder.WriteString("\n- name: Copy " + license.Name + " into " + plan.family() + "\n  ansible.builtin.copy:\n    dest: " +
+				yamlQuote(joinTargetPath(script.Platform, licensesDirectory, license.Name)) + "\n    mode: \"0644\"\n    content: " + yamlQuote(script.LicenseContents[i]) + "\n")
+		}
+	}
+	return builder.String()
+}
+
+// Write a session out as a script in the format you asked for. Returns where it was saved.
+func exportSessionScript(rl *readline.Instance, args []string, format string, script sessionScript, licensePaths []string) (string, error) {
+	licenses, err := prepareLicenseSources(licensePaths)
+	if err != nil {
+		return "", err
+	}
+	script.Licenses = licenses
+	for _, license := range licenses {
+		data, err := os.ReadFile(license.Path)
+		if err != nil {
+			return "", err
+		}
+		script.LicenseContents = append(script.LicenseContents, string(data))
+	}
+
+	switch format {
+	case "shell":
+		return writeExport(rl, args, "install_matlab.sh", script.shell(), 0755)
+	case "powershell":
+		return writeExport(rl, args, "install_matlab.ps1", script.powerShell(), 0644)
+	case "ansible":
+		return writeExport(rl, args, "install_matlab.yml", script.ansible(), 0644)
+	}
+	return "", fmt.Errorf("unknown script format \"%s\"", format)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Read /etc/os-release (or any file like it) into its keys and values, with the quotes taken off.
+func readOSRelease(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		values[key] = strings.Trim(value, "\"'")
+	}
+	return values, scanner.Err()
+}
+
+// A readable description of the OS we're running on, such as "Ubuntu 22.04.4 LTS (linux/amd64)".
+func osDescription() string {
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	if runtime.GOOS == "linux" {
+		if osRelease, err := readOSRelease("/etc/os-release"); err == nil && osRelease["PRETTY_NAME"] != "" {
+			return osRelease["PRETTY_NAME"] + " (" + platform + ")"
+		}
+	}
+	return platform
+}
+
+// The version of glibc we're running on, such as "2.35". Empty if it couldn't be found, like on distros that use musl instead.
+func glibcVersion() string {
+	if output, err := exec.Command("getconf", "GNU_LIBC_VERSION").Output(); err == nil {
+		fields := strings.Fields(string(output)) // "glibc 2.35"
+		if len(fields) == 2 && fields[0] == "glibc" {
+			return fields[1]
+		}
+	}
+
+	// ldd's first line ends with the version, such as "ldd (Ubuntu GLIBC 2.35-0ubuntu3.8) 2.35".
+	if output, err := exec.Command("ldd", "--version").Output(); err == nil {
+		firstLine, _, _ := strings.Cut(string(output), "\n")
+		if fields := strings.Fields(firstLine); len(fields) > 0 && strings.Contains(strings.ToLower(firstLine), "libc") {
+			return fields[len(fields)-1]
+		}
+	}
+	return ""
+}
+
+// Compare dotted version numbers such as "2.17" and "2.35" part by part. Parts that aren't numbers count as 0.
+func versionAtLeast(version string, minimum string) bool {
+	versionParts := strings.Split(version, ".")
+	minimumParts := strings.Split(minimum, ".")
+	for i := 0; i < len(versionParts) || i < len(minimumParts); i++ {
+		var have, want int
+		if i < len(versionParts) {
+			have, _ = strconv.Atoi(versionParts[i])
+		}
+		if i < len(minimumParts) {
+			want, _ = strconv.Atoi(minimumParts[i])
+		}
+		if have != want {
+			return have > want
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	readline "github.com/Jestzer/readlineJestzer"
+	"github.com/fatih/color"
+)
+
+// Walk the user through removing products or an entire release from an existing installation using MPM.
+func uninstallProducts(rl *readline.Instance, mpmFullPath string, platform string) {
+	redText := color.New(color.FgRed).SprintFunc()
+	greenText := color.New(color.FgHiGreen).SprintFunc()
+
+	target := selectInstallation(rl, platform, "uninstall from")
+
+	if len(target.Products) == 0 {
+		fmt.Println(redText("No installed products could be found in \"" + target.Path + "\". It may already be partially removed."))
+	} else {
+		fmt.Println("The following products are installed in " + target.Release + " at \"" + target.Path + "\":")
+		for _, product := range target.Products {
+			fmt.Println("- " + product)
+		}
+	}
+
+	// Figure out what's being removed.
+	var productsToRemove []string
+	wholeRelease := false
+	for {
+		productsInput := promptUser(rl, "Enter the products you would like to uninstall. Use the same syntax as MPM to specify products. "+
+			"Press Enter to uninstall the entire release.")
+		if productsInput == "" {
+			wholeRelease = true
+			productsToRemove = target.Products
+			break
+		}
+
+		productsToRemove = strings.Fields(productsInput)
+		missingProducts := checkProductsExist(productsToRemove, target.Products)
+		if len(missingProducts) > 0 {
+			fmt.Println(redText("The following products are not installed here:"))
+			for _, missingProduct := range missingProducts {
+				fmt.Println(redText("- " + missingProduct))
+			}
+			fmt.Println(redText("Please try again and check for any typos."))
+			continue
+		}
+
+		// Removing every product is the same as removing the release.
+		if len(checkProductsExist(target.Products, productsToRemove)) == 0 {
+			wholeRelease = true
+		}
+		break
+	}
+
+	// Without a product list, there's nothing to tell MPM to remove, so the directory itself is all that's left to delete.
+	if len(productsToRemove) == 0 {
+		fmt.Println(redText("There are no products for MPM to uninstall, so it won't be run."))
+		fmt.Println("Calculating how much disk space will be freed, please wait.")
+		size, err := directorySize(target.Path)
+		if err != nil {
+			fmt.Println(redText("Could not calculate the size of the installation: ", err))
+		} else {
+			fmt.Println("Deleting \"" + target.Path + "\" will free " + formatBytes(size) + ".")
+		}
+		if !promptYesNo(rl, "Would you like to delete \""+target.Path+"\" instead?") {
+			fmt.Println("Nothing was uninstalled.")
+			return
+		}
+		if err := os.RemoveAll(target.Path); err != nil {
+			fmt.Println(redText("Error deleting \""+target.Path+"\": ", err))
+			return
+		}
+		if platform == "linux" {
+			for _, removedPath := range removeDesktopEntry(target.Path, target.Release) {
+				fmt.Println("Removed \"" + removedPath + "\".")
+			}
+		}
+		fmt.Println(greenText("Uninstallation finished!"))
+		return
+	}
+
+	// Show what you'll be getting back before anything is deleted.
+	fmt.Println("Calculating how much disk space will be freed, please wait.")
+	if wholeRelease {
+		size, err := directorySize(target.Path)
+		if err != nil {
+			fmt.Println(redText("Could not calculate the size of the installation: ", err))
+		} else {
+			fmt.Println("Uninstalling " + target.Release + " will free " + formatBytes(size) + ".")
+		}
+	} else {
+		size, unknownProducts := estimateProductsSize(target.Path, productsToRemove)
+		fmt.Println("Uninstalling these products will free roughly " + formatBytes(size) + ".")
+		if len(unknownProducts) > 0 {
+			fmt.Println("This estimate does not include: " + strings.Join(unknownProducts, ", "))
+		}
+	}
+
+	if !promptYesNo(rl, "Are you sure you want to continue?") {
+		fmt.Println("Nothing was uninstalled.")
+		return
+	}
+
+	// Always name the products, even for a whole release, rather than count on what MPM does without a list.
+	cmdArgs := []string{
+		mpmFullPath,
+		"uninstall",
+		"--destination=" + target.Path,
+		"--products",
+	}
+	cmdArgs = append(cmdArgs, productsToRemove...)
+
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	cmd.Stdout = &customWriter{writer: os.Stdout}
+	cmd.Stderr = &customWriter{writer: os.Stderr}
+	fmt.Println("Uninstalling, please wait.")
+	err := cmd.Run()
+	if err != nil {
+		fmt.Println(redText("An error occurred while uninstalling. See the error above for more information. ", err))
+		return
+	}
+
+	// MPM doesn't know about the application menu entry, so clean that up ourselves.
+	if wholeRelease && platform == "linux" {
+		for _, removedPath := range removeDesktopEntry(target.Path, target.Release) {
+			fmt.Println("Removed \"" + removedPath + "\".")
+		}
+	}
+
+	// MPM leaves licenses and other files it didn't install behind.
+	if wholeRelease {
+		if _, err := os.Stat(target.Path); err == nil {
+			if promptYesNo(rl, "Some files were left behind in \""+target.Path+"\", such as license files. Would you like to delete this directory as well?") {
+				err := os.RemoveAll(target.Path)
+				if err != nil {
+					fmt.Println(redText("Error deleting \""+target.Path+"\": ", err))
+					return
+				}
+			}
+		}
+	}
+
+	fmt.Println(greenText("Uninstallation finished!"))
+}
+
+// Let the user pick one of the installations found in the default locations or recorded by this program, or type in the path to one.
+func selectInstallation(rl *readline.Instance, platform string, action string) installation {
+	redText := color.New(color.FgRed).SprintFunc()
+
+	installations := findInstallations(append(defaultInstallationRoots(platform), recordedInstallations()...))
+	if len(installations) > 0 {
+		fmt.Println("The following installations were found:")
+		for i, found := range installations {
+			fmt.Printf("%d. %s (%s)\n", i+1, found.Release, found.Path)
+		}
+	}
+
+	for {
+		var choice string
+		if len(installations) > 0 {
+			choice = promptUser(rl, "Enter the number of the installation you would like to "+action+", or the full path to a different installation.")
+		} else {
+			choice = promptUser(rl, "No installations were found. Enter the full path to the installation you would like to "+action+".")
+		}
+
+		if choice == "" {
+			continue
+		}
+		if number, err := strconv.Atoi(choice); err == nil {
+			if number < 1 || number > len(installations) {
+				fmt.Println(redText("Invalid selection. Enter a number from the list above."))
+				continue
+			}
+			return installations[number-1]
+		}
+
+		fullPath, err := filepath.Abs(choice)
+		if err != nil {
+			fmt.Println(redText("Error reading the path you entered: ", err))
+			continue
+		}
+		found, err := readInstallation(fullPath)
+		if err != nil {
+			fmt.Println(redText("\"" + fullPath + "\" does not appear to be an installation of MathWorks products: " + err.Error()))
+			continue
+		}
+		return found
+	}
+}
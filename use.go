@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+// The programs you'd want on your PATH. Only the ones an installation actually has get linked.
+var linkedPrograms = []string{"matlab", "mex", "mcc"}
+
+// Pick which MATLAB installation "matlab" runs by pointing a "current" link at it, linking its programs into a bin directory,
+// and putting it on your PATH for future logins.
+// Usage: use [<release> | <path>] [--root <dir>]... [--bin-dir <dir>]
+// Without a release, the installations we know about are listed instead.
+func useRelease(args []string) error {
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("the use command isn't available on Windows")
+	}
+
+	var installations []installation
+	for _, found := range findInstallations(knownInstallationPatterns(args)) {
+		if isMATLABRoot(found.Path) {
+			installations = append(installations, found)
+		}
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return printUsableInstallations(installations)
+	}
+	chosen, err := chooseInstallationToUse(installations, args[0])
+	if err != nil {
+		printUsableInstallations(installations)
+		return err
+	}
+	if problems := incompleteInstallationProblems(chosen); len(problems) > 0 {
+		return fmt.Errorf("\"%s\" is incomplete, so it won't be used: %s", chosen.Path, strings.Join(problems, "; "))
+	}
+
+	currentLink, err := currentLinkPath(chosen.Path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(currentLink), 0755); err != nil {
+		return err
+	}
+	if err := replaceSymlink(chosen.Path, currentLink); err != nil {
+		return err
+	}
+	fmt.Println("\"" + currentLink + "\" now points to \"" + chosen.Path + "\".")
+
+	// The programs go through the "current" link, so switching again later only needs to change that.
+	binDirectory, err := programLinkDirectory(args)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(binDirectory, 0755); err != nil {
+		return err
+	}
+	for _, program := range linkedPrograms {
+		if _, err := os.Stat(filepath.Join(chosen.Path, "bin", program)); err != nil {
+			continue
+		}
+		linkPath := filepath.Join(binDirectory, program)
+		if err := replaceSymlink(filepath.Join(currentLink, "bin", program), linkPath); err != nil {
+			fmt.Println("Could not link " + program + ": " + err.Error())
+			continue
+		}
+		fmt.Println("Linked \"" + linkPath + "\".")
+	}
+
+	savedTo, err := writeEnvironmentSetting("PATH", filepath.Join(currentLink, "bin")+":$PATH")
+	if err != nil {
+		return fmt.Errorf("could not add %s to your PATH: %w", chosen.Release, err)
+	}
+	fmt.Println("Your PATH setting has been saved to " + savedTo + ". It will take effect the next time you log in.")
+	fmt.Println(chosen.Release + " is now in use.")
+	return nil
+}
+
+// Find an installation by its release or its path. Several installations of the same release need to be picked by path.
+func chooseInstallationToUse(installations []installation, target string) (installation, error) {
+	if _, err := os.Stat(target); err == nil && strings.ContainsAny(target, `/\`) {
+		fullPath, err := filepath.Abs(target)
+		if err != nil {
+			return installation{}, err
+		}
+		if resolvedPath, err := filepath.EvalSymlinks(fullPath); err == nil {
+			fullPath = resolvedPath
+		}
+		return readInstallation(fullPath)
+	}
+
+	var matches []installation
+	for _, found := range installations {
+		if strings.EqualFold(found.Release, target) {
+			matches = append(matches, found)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return installation{}, fmt.Errorf("no installation of %s could be found. Use --root to tell me where to look", target)
+	case 1:
+		return matches[0], nil
+	}
+	return installation{}, fmt.Errorf("%s is installed in more than one place. Enter the path of the one you'd like to use instead", target)
+}
+
+// Reasons an installation shouldn't be switched to.
+func incompleteInstallationProblems(found installation) []string {
+	var problems []string
+	if launcher := checkLauncher(found.Path); !launcher.Passed {
+		problems = append(problems, launcher.Description+": "+launcher.Detail)
+	}
+	hasMATLAB := false
+	for _, product := range found.Products {
+		if product == "MATLAB" {
+			hasMATLAB = true
+			break
+		}
+	}
+	if !hasMATLAB {
+		problems = append(problems, "MATLAB itself isn't installed")
+	}
+	return problems
+}
+
+// Where the "current" link goes. That's next to the installations themselves if you can write there, and somewhere of your own
+// otherwise, since installations in places like /usr/local/MATLAB usually belong to root.
+func currentLinkPath(installPath string) (string, error) {
+	parentDirectory := filepath.Dir(installPath)
+	if runtime.GOOS == "darwin" {
+		if directoryWritable(parentDirectory) {
+			return filepath.Join(parentDirectory, "MATLAB_current.app"), nil
+		}
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(homeDir, "Applications", "MATLAB_current.app"), nil
+	}
+	if directoryWritable(parentDirectory) {
+		return filepath.Join(parentDirectory, "current"), nil
+	}
+	dataDirectory, err := desktopDataDirectories(false)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDirectory, "matlab", "current"), nil
+}
+
+// Check if we can create files in a directory by trying it, which is the only answer that accounts for ACLs and read-only mounts.
+func directoryWritable(directory string) bool {
+	file, err := os.CreateTemp(directory, ".mpm-write-test")
+	if err != nil {
+		return false
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return true
+}
+
+// Where matlab, mex, and mcc get linked. Everyone's PATH already has /usr/local/bin, so that's used when we're root.
+func programLinkDirectory(args []string) (string, error) {
+	if binDirectories := argumentValues(args, "--bin-dir"); len(binDirectories) > 0 {
+		return binDirectories[len(binDirectories)-1], nil
+	}
+	if os.Geteuid() == 0 {
+		return "/usr/local/bin", nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".local", "bin"), nil
+}
+
+// Point a symlink somewhere new without there ever being a moment where it's missing. Anything that isn't a symlink is left alone.
+func replaceSymlink(target string, linkPath string) error {
+	if info, err := os.Lstat(linkPath); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("\"%s\" already exists and isn't a symlink, so it was left alone", linkPath)
+	}
+	tempLink := linkPath + ".mpm-new"
+	os.Remove(tempLink)
+	if err := os.Symlink(target, tempLink); err != nil {
+		return err
+	}
+	if err := os.Rename(tempLink, linkPath); err != nil {
+		os.Remove(tempLink)
+		return err
+	}
+	return nil
+}
+
+// List the installations "use" knows about, marking the ones in use and the ones that are incomplete.
+func printUsableInstallations(installations []installation) error {
+	if len(installations) == 0 {
+		fmt.Println("No installations of MATLAB were found.")
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "\tRELEASE\tUPDATE\tPATH\tSTATUS")
+	for _, found := range installations {
+		marker := ""
+		if currentLink, err := currentLinkPath(found.Path); err == nil {
+			if resolvedPath, err := filepath.EvalSymlinks(currentLink); err == nil && resolvedPath == found.Path {
+				marker = "*"
+			}
+		}
+		status := "ready"
+		if problems := incompleteInstallationProblems(found); len(problems) > 0 {
+			status = "incomplete"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", marker, found.Release, found.Update, found.Path, status)
+	}
+	return writer.Flush()
+}
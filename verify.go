@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/fatih/color"
+)
+
+// One line of the checklist shown after installing.
+type verificationCheck struct {
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+	Detail      string `json:"detail,omitempty"` // Why it failed.
+}
+
+// Make sure MPM actually left behind what we asked for. licensePaths are the license files that should now be in place.
+func verifyInstallation(plan installPlan, licensePaths []string) []verificationCheck {
+	var checks []verificationCheck
+
+	// Polyspace has several launchers depending on the products, so only MATLAB's gets checked.
+	if !plan.Polyspace {
+		checks = append(checks, checkLauncher(plan.Destination))
+	}
+
+	found, err := readInstallation(plan.Destination)
+	releaseCheck := verificationCheck{Description: "VersionInfo.xml lists " + plan.Release}
+	if err != nil {
+		releaseCheck.Detail = err.Error()
+	} else if found.Release != plan.Release {
+		releaseCheck.Detail = "it lists " + found.Release + " instead"
+	} else {
+		releaseCheck.Passed = true
+	}
+	checks = append(checks, releaseCheck)
+
+	installed := make(map[string]bool)
+	for _, product := range found.Products {
+		installed[product] = true
+	}
+	for _, product := range plan.Products {
+		productCheck := verificationCheck{Description: product + " is installed"}
+		if !installed[product] {
+			productCheck.Detail = "it isn't listed in appdata/products"
+		} else {
+			productCheck.Passed = true
+			for _, folder := range productFolders[product] {
+				if _, err := os.Stat(filepath.Join(plan.Destination, "toolbox", folder)); err != nil {
+					productCheck.Passed = false
+					productCheck.Detail = "toolbox/" + folder + " is missing"
+					break
+				}
+			}
+		}
+		checks = append(checks, productCheck)
+	}
+
+	for _, licensePath := range licensePaths {
+		licenseCheck := verificationCheck{Description: "License file " + licensePath + " is in place"}
+		file, err := os.Open(licensePath)
+		if err != nil {
+			licenseCheck.Detail = err.Error()
+		} else {
+			file.Close()
+			licenseCheck.Passed = true
+		}
+		checks = append(checks, licenseCheck)
+	}
+	return checks
+}
+
+// MATLAB's launcher has to be there and be something you can run.
+func checkLauncher(installPath string) verificationCheck {
+	launcher := filepath.Join(installPath, "bin", "matlab")
+	if runtime.GOOS == "windows" {
+		launcher += ".exe"
+	}
+	check := verificationCheck{Description: "The launcher " + launcher + " can be run"}
+	info, err := os.Stat(launcher)
+	switch {
+	case err != nil:
+		check.Detail = err.Error()
+	case info.IsDir():
+		check.Detail = "it's a folder"
+	case runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0:
+		check.Detail = "it isn't executable"
+	default:
+		check.Passed = true
+	}
+	return check
+}
+
+// Show the checklist. Returns true if everything passed.
+func printVerification(installPath string, checks []verificationCheck) bool {
+	greenText := color.New(color.FgHiGreen).SprintFunc()
+	redText := color.New(color.FgRed).SprintFunc()
+
+	fmt.Println("Checking the installation in \"" + installPath + "\":")
+	allPassed := true
+	for _, check := range checks {
+		if check.Passed {
+			fmt.Println(greenText("[PASS] " + check.Description))
+		} else {
+			allPassed = false
+			fmt.Println(redText("[FAIL] " + check.Description + ": " + check.Detail))
+		}
+	}
+	return allPassed
+}